@@ -338,7 +338,7 @@ func PauseAlert(c *m.ReqContext, dto dtos.PauseAlertCommand) Response {
 	}
 
 	guardian := guardian.New(query.Result.DashboardId, c.OrgId, c.SignedInUser)
-	if canEdit, err := guardian.CanEdit(); err != nil || !canEdit {
+	if canPause, err := guardian.CanPauseAlerts(); err != nil || !canPause {
 		if err != nil {
 			return Error(500, "Error while checking permissions for Alert", err)
 		}
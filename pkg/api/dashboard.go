@@ -62,6 +62,7 @@ func (hs *HTTPServer) GetDashboard(c *m.ReqContext) Response {
 	canEdit, _ := guardian.CanEdit()
 	canSave, _ := guardian.CanSave()
 	canAdmin, _ := guardian.CanAdmin()
+	canViewProvisioningSource, _ := guardian.CanViewProvisioningSource()
 
 	isStarred, err := isDashboardStarredByUser(c, dash.Id)
 	if err != nil {
@@ -114,6 +115,10 @@ func (hs *HTTPServer) GetDashboard(c *m.ReqContext) Response {
 
 	if provisioningData != nil {
 		meta.Provisioned = true
+	}
+
+	// The file path is only shown to users allowed to see where the dashboard is provisioned from
+	if provisioningData != nil && canViewProvisioningSource {
 		meta.ProvisionedExternalId, err = filepath.Rel(
 			hs.ProvisioningService.GetDashboardProvisionerResolvedPath(provisioningData.Name),
 			provisioningData.ExternalId,
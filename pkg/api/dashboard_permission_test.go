@@ -113,6 +113,37 @@ func TestDashboardPermissionApiEndpoint(t *testing.T) {
 			})
 		})
 
+		Convey("Given the dashboard inherits permissions from its folder", func() {
+			origNewGuardian := guardian.New
+			guardian.MockDashboardGuardian(&guardian.FakeDashboardGuardian{
+				CanAdminValue: true,
+				GetAclValue: []*m.DashboardAclInfoDTO{
+					{OrgId: 1, DashboardId: 2, UserId: 2, Permission: m.PERMISSION_VIEW, Inherited: true, IsFolder: true, Uid: "folder-uid", Slug: "folder"},
+					{OrgId: 1, DashboardId: 1, UserId: 3, Permission: m.PERMISSION_EDIT, Uid: "dash-uid", Slug: "dash"},
+				},
+			})
+
+			getDashboardQueryResult := m.NewDashboard("Dash")
+			bus.AddHandler("test", func(query *m.GetDashboardQuery) error {
+				query.Result = getDashboardQueryResult
+				return nil
+			})
+
+			loggedInUserScenarioWithRole("When calling GET on", "GET", "/api/dashboards/id/1/permissions", "/api/dashboards/id/:id/permissions", m.ROLE_ADMIN, func(sc *scenarioContext) {
+				callGetDashboardPermissions(sc)
+				So(sc.resp.Code, ShouldEqual, 200)
+				respJSON, err := simplejson.NewJson(sc.resp.Body.Bytes())
+				So(err, ShouldBeNil)
+				So(len(respJSON.MustArray()), ShouldEqual, 2)
+				So(respJSON.GetIndex(0).Get("url").MustString(), ShouldEqual, "/dashboards/f/folder-uid/folder")
+				So(respJSON.GetIndex(1).Get("url").MustString(), ShouldEqual, "/d/dash-uid/dash")
+			})
+
+			Reset(func() {
+				guardian.New = origNewGuardian
+			})
+		})
+
 		Convey("When trying to update permissions with duplicate permissions", func() {
 			origNewGuardian := guardian.New
 			guardian.MockDashboardGuardian(&guardian.FakeDashboardGuardian{
@@ -946,16 +946,34 @@ func TestDashboardApiEndpoint(t *testing.T) {
 			})
 		})
 
-		loggedInUserScenarioWithRole("When calling GET on", "GET", "/api/dashboards/uid/dash", "/api/dashboards/uid/:uid", m.ROLE_EDITOR, func(sc *scenarioContext) {
-			mock := provisioning.NewProvisioningServiceMock()
-			mock.GetDashboardProvisionerResolvedPathFunc = func(name string) string {
-				return "/tmp/grafana/dashboards"
-			}
+		Convey("When user is an Org Admin", func() {
+			loggedInUserScenarioWithRole("When calling GET on", "GET", "/api/dashboards/uid/dash", "/api/dashboards/uid/:uid", m.ROLE_ADMIN, func(sc *scenarioContext) {
+				mock := provisioning.NewProvisioningServiceMock()
+				mock.GetDashboardProvisionerResolvedPathFunc = func(name string) string {
+					return "/tmp/grafana/dashboards"
+				}
+
+				dash := GetDashboardShouldReturn200WithConfig(sc, mock)
 
-			dash := GetDashboardShouldReturn200WithConfig(sc, mock)
+				Convey("Should return relative path to provisioning file", func() {
+					So(dash.Meta.ProvisionedExternalId, ShouldEqual, "test/dashboard1.json")
+				})
+			})
+		})
 
-			Convey("Should return relative path to provisioning file", func() {
-				So(dash.Meta.ProvisionedExternalId, ShouldEqual, "test/dashboard1.json")
+		Convey("When user is an Org Editor", func() {
+			loggedInUserScenarioWithRole("When calling GET on", "GET", "/api/dashboards/uid/dash", "/api/dashboards/uid/:uid", m.ROLE_EDITOR, func(sc *scenarioContext) {
+				mock := provisioning.NewProvisioningServiceMock()
+				mock.GetDashboardProvisionerResolvedPathFunc = func(name string) string {
+					return "/tmp/grafana/dashboards"
+				}
+
+				dash := GetDashboardShouldReturn200WithConfig(sc, mock)
+
+				Convey("Should mark the dashboard provisioned without the path", func() {
+					So(dash.Meta.Provisioned, ShouldBeTrue)
+					So(dash.Meta.ProvisionedExternalId, ShouldEqual, "")
+				})
 			})
 		})
 	})
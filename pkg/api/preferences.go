@@ -4,6 +4,7 @@ import (
 	"github.com/grafana/grafana/pkg/api/dtos"
 	"github.com/grafana/grafana/pkg/bus"
 	m "github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/guardian"
 )
 
 // POST /api/preferences/set-home-dash
@@ -12,6 +13,10 @@ func SetHomeDashboard(c *m.ReqContext, cmd m.SavePreferencesCommand) Response {
 	cmd.UserId = c.UserId
 	cmd.OrgId = c.OrgId
 
+	if rsp := checkCanPinToPersonalHome(c, cmd.HomeDashboardId); rsp != nil {
+		return rsp
+	}
+
 	if err := bus.Dispatch(&cmd); err != nil {
 		return Error(500, "Failed to set home dashboard", err)
 	}
@@ -24,6 +29,19 @@ func GetUserPreferences(c *m.ReqContext) Response {
 	return getPreferencesFor(c.OrgId, c.UserId, 0)
 }
 
+func checkCanPinToPersonalHome(c *m.ReqContext, dashboardID int64) Response {
+	if dashboardID == 0 {
+		return nil
+	}
+
+	guardian := guardian.New(dashboardID, c.OrgId, c.SignedInUser)
+	if canPin, err := guardian.CanPinToPersonalHome(); err != nil || !canPin {
+		return dashboardGuardianResponse(err)
+	}
+
+	return nil
+}
+
 func getPreferencesFor(orgID, userID, teamID int64) Response {
 	prefsQuery := m.GetPreferencesQuery{UserId: userID, OrgId: orgID, TeamId: teamID}
 
@@ -42,6 +60,10 @@ func getPreferencesFor(orgID, userID, teamID int64) Response {
 
 // PUT /api/user/preferences
 func UpdateUserPreferences(c *m.ReqContext, dtoCmd dtos.UpdatePrefsCmd) Response {
+	if rsp := checkCanPinToPersonalHome(c, dtoCmd.HomeDashboardID); rsp != nil {
+		return rsp
+	}
+
 	return updatePreferencesFor(c.OrgId, c.UserId, 0, &dtoCmd)
 }
 
@@ -69,5 +91,12 @@ func GetOrgPreferences(c *m.ReqContext) Response {
 
 // PUT /api/org/preferences
 func UpdateOrgPreferences(c *m.ReqContext, dtoCmd dtos.UpdatePrefsCmd) Response {
+	if dtoCmd.HomeDashboardID != 0 {
+		guardian := guardian.New(dtoCmd.HomeDashboardID, c.OrgId, c.SignedInUser)
+		if canSet, err := guardian.CanSetAsOrgLanding(); err != nil || !canSet {
+			return dashboardGuardianResponse(err)
+		}
+	}
+
 	return updatePreferencesFor(c.OrgId, 0, 0, &dtoCmd)
 }
@@ -92,6 +92,12 @@ var (
 	// MDBDataSourceQueryByID is a metric counter for getting datasource by id
 	MDBDataSourceQueryByID prometheus.Counter
 
+	// MGuardianDecisions is a metric counter for dashboard permission checks by permission and outcome
+	MGuardianDecisions *prometheus.CounterVec
+
+	// MGuardianErrors is a metric counter for dashboard permission checks that failed
+	MGuardianErrors prometheus.Counter
+
 	// LDAPUsersSyncExecutionTime is a metric summary for LDAP users sync execution duration
 	LDAPUsersSyncExecutionTime prometheus.Summary
 )
@@ -318,6 +324,18 @@ func init() {
 		Namespace: exporterName,
 	})
 
+	MGuardianDecisions = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name:      "guardian_decisions_total",
+		Help:      "counter for dashboard permission checks by permission and outcome",
+		Namespace: exporterName,
+	}, []string{"permission", "outcome"})
+
+	MGuardianErrors = newCounterStartingAtZero(prometheus.CounterOpts{
+		Name:      "guardian_errors_total",
+		Help:      "counter for dashboard permission checks that failed",
+		Namespace: exporterName,
+	})
+
 	LDAPUsersSyncExecutionTime = prometheus.NewSummary(prometheus.SummaryOpts{
 		Name:      "ldap_users_sync_execution_time",
 		Help:      "summary for LDAP users sync execution duration",
@@ -456,6 +474,8 @@ func initMetricVars() {
 		MAwsCloudWatchListMetrics,
 		MAwsCloudWatchGetMetricData,
 		MDBDataSourceQueryByID,
+		MGuardianDecisions,
+		MGuardianErrors,
 		LDAPUsersSyncExecutionTime,
 		MAlertingActiveAlerts,
 		MStatTotalDashboards,
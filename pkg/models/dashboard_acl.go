@@ -14,12 +14,32 @@ const (
 )
 
 func (p PermissionType) String() string {
-	names := map[int]string{
-		int(PERMISSION_VIEW):  "View",
-		int(PERMISSION_EDIT):  "Edit",
-		int(PERMISSION_ADMIN): "Admin",
+	switch p {
+	case PERMISSION_VIEW:
+		return "View"
+	case PERMISSION_EDIT:
+		return "Edit"
+	case PERMISSION_ADMIN:
+		return "Admin"
 	}
-	return names[int(p)]
+	return ""
+}
+
+// ActionNameForPermission returns the name the api and the ui use for the permission, or an
+// empty name for an unknown permission
+func ActionNameForPermission(p PermissionType) string {
+	return p.String()
+}
+
+// PermissionForActionName is the inverse of ActionNameForPermission. Unknown names return the
+// zero PermissionType
+func PermissionForActionName(name string) PermissionType {
+	for _, p := range []PermissionType{PERMISSION_VIEW, PERMISSION_EDIT, PERMISSION_ADMIN} {
+		if p.String() == name {
+			return p
+		}
+	}
+	return 0
 }
 
 // Typed errors
@@ -110,3 +130,11 @@ type GetDashboardAclInfoListQuery struct {
 	OrgId       int64
 	Result      []*DashboardAclInfoDTO
 }
+
+// GetDashboardsAclInfoListQuery returns the acl of several dashboards in a single query, keyed by
+// dashboard id. The acl of each dashboard is the same as returned by GetDashboardAclInfoListQuery
+type GetDashboardsAclInfoListQuery struct {
+	DashboardIds []int64
+	OrgId        int64
+	Result       map[int64][]*DashboardAclInfoDTO
+}
@@ -18,4 +18,17 @@ func TestDashboardAclModel(t *testing.T) {
 			So(printed, ShouldEqual, "View")
 		})
 	})
+
+	Convey("When mapping permission names", t, func() {
+		Convey("Should round trip every permission", func() {
+			for _, permission := range []PermissionType{PERMISSION_VIEW, PERMISSION_EDIT, PERMISSION_ADMIN} {
+				So(PermissionForActionName(ActionNameForPermission(permission)), ShouldEqual, permission)
+			}
+		})
+
+		Convey("Should map unknown names and permissions to zero values", func() {
+			So(PermissionForActionName("Owner"), ShouldEqual, PermissionType(0))
+			So(ActionNameForPermission(PermissionType(8)), ShouldEqual, "")
+		})
+	})
 }
@@ -32,6 +32,7 @@ var (
 	ErrDashboardUidToLong                        = errors.New("uid to long. max 40 characters")
 	ErrDashboardCannotSaveProvisionedDashboard   = errors.New("Cannot save provisioned dashboard")
 	ErrDashboardCannotDeleteProvisionedDashboard = errors.New("provisioned dashboard cannot be deleted")
+	ErrDashboardNotProvisioned                   = errors.New("Dashboard is not provisioned")
 	RootFolderName                               = "General"
 )
 
@@ -304,6 +305,12 @@ type GetDashboardsQuery struct {
 	Result       []*Dashboard
 }
 
+type GetDashboardsByFolderIdQuery struct {
+	OrgId    int64
+	FolderId int64
+	Result   []*Dashboard
+}
+
 type GetDashboardPermissionsForUserQuery struct {
 	DashboardIds []int64
 	OrgId        int64
@@ -106,3 +106,10 @@ type GetPlaylistItemsByIdQuery struct {
 	PlaylistId int64
 	Result     *[]PlaylistItem
 }
+
+// GetPlaylistsByDashboardIdQuery returns the playlists of the org with an item for the dashboard
+type GetPlaylistsByDashboardIdQuery struct {
+	DashboardId int64
+	OrgId       int64
+	Result      Playlists
+}
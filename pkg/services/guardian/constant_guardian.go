@@ -0,0 +1,399 @@
+package guardian
+
+import (
+	m "github.com/grafana/grafana/pkg/models"
+)
+
+// constantGuardian answers every check the same way without any store lookups. It is not bound
+// to a dashboard, so acls, blocked lists and folder children are always empty
+type constantGuardian struct {
+	allowed bool
+}
+
+// NewAllowAllGuardian returns a guardian that allows everything without any store lookups, for
+// tests that don't care about permissions
+func NewAllowAllGuardian() DashboardGuardian {
+	return &constantGuardian{allowed: true}
+}
+
+// NewDenyAllGuardian returns a guardian that denies everything without any store lookups, for
+// tests that don't care about permissions
+func NewDenyAllGuardian() DashboardGuardian {
+	return &constantGuardian{allowed: false}
+}
+
+func (g *constantGuardian) orError(permission m.PermissionType) error {
+	if g.allowed {
+		return nil
+	}
+
+	return PermissionDeniedError{Requirement: permission.String()}
+}
+
+func (g *constantGuardian) CanSave() (bool, error) {
+	return g.allowed, nil
+}
+
+func (g *constantGuardian) CanEdit() (bool, error) {
+	return g.allowed, nil
+}
+
+func (g *constantGuardian) CanView() (bool, error) {
+	return g.allowed, nil
+}
+
+func (g *constantGuardian) CanAdmin() (bool, error) {
+	return g.allowed, nil
+}
+
+func (g *constantGuardian) HasPermission(permission m.PermissionType) (bool, error) {
+	return g.allowed, nil
+}
+
+func (g *constantGuardian) CheckPermissionBeforeUpdate(permission m.PermissionType, updatePermissions []*m.DashboardAcl) (bool, error) {
+	return g.allowed, nil
+}
+
+func (g *constantGuardian) GetAcl() ([]*m.DashboardAclInfoDTO, error) {
+	return []*m.DashboardAclInfoDTO{}, nil
+}
+
+func (g *constantGuardian) CanSetSharedTimeRange() (bool, error) {
+	return g.allowed, nil
+}
+
+func (g *constantGuardian) CanDeleteWithDependencies() (bool, []string, error) {
+	return g.allowed, []string{}, nil
+}
+
+func (g *constantGuardian) CanCreateWithDefaultTemplate(folderID int64) (bool, error) {
+	return g.allowed, nil
+}
+
+func (g *constantGuardian) CanExportAcl() (bool, error) {
+	return g.allowed, nil
+}
+
+func (g *constantGuardian) ExportAcl() ([]*m.DashboardAclInfoDTO, error) {
+	return []*m.DashboardAclInfoDTO{}, nil
+}
+
+func (g *constantGuardian) CanEmbedInto(parentDashboardID int64) (bool, error) {
+	return g.allowed, nil
+}
+
+func (g *constantGuardian) CanRestoreVersion(version int) (bool, error) {
+	return g.allowed, nil
+}
+
+func (g *constantGuardian) CanAckAlerts() (bool, error) {
+	return g.allowed, nil
+}
+
+func (g *constantGuardian) CanTrash() (bool, error) {
+	return g.allowed, nil
+}
+
+func (g *constantGuardian) CanPurge() (bool, error) {
+	return g.allowed, nil
+}
+
+func (g *constantGuardian) CanDuplicateFolder(targetParentID int64) (bool, error) {
+	return g.allowed, nil
+}
+
+func (g *constantGuardian) CanManageDatasourcePropagation() (bool, error) {
+	return g.allowed, nil
+}
+
+func (g *constantGuardian) CanBulkTagFolder() (bool, error) {
+	return g.allowed, nil
+}
+
+func (g *constantGuardian) GetEditableFolderDashboards() ([]int64, error) {
+	return []int64{}, nil
+}
+
+func (g *constantGuardian) CanShareToChannel(integration string) (bool, error) {
+	return g.allowed, nil
+}
+
+func (g *constantGuardian) CanCreateFromTemplate(targetFolderID int64, dsNames []string) (bool, error) {
+	return g.allowed, nil
+}
+
+func (g *constantGuardian) IsSoleAdmin() (bool, error) {
+	return false, nil
+}
+
+func (g *constantGuardian) CanPauseAlerts() (bool, error) {
+	return g.allowed, nil
+}
+
+func (g *constantGuardian) CanAttachToIncident(incidentID string) (bool, error) {
+	return g.allowed, nil
+}
+
+func (g *constantGuardian) CanSetAsTeamDefault(teamID int64) (bool, error) {
+	return g.allowed, nil
+}
+
+func (g *constantGuardian) CanConfigureAccessRequests() (bool, error) {
+	return g.allowed, nil
+}
+
+func (g *constantGuardian) CanRequestAccess(level m.PermissionType) (bool, error) {
+	return g.allowed, nil
+}
+
+func (g *constantGuardian) CanApproveAccessRequest(targetUserID int64, level m.PermissionType) (bool, error) {
+	return g.allowed, nil
+}
+
+func (g *constantGuardian) CanPinToPersonalHome() (bool, error) {
+	return g.allowed, nil
+}
+
+func (g *constantGuardian) CanEditLinks() (bool, error) {
+	return g.allowed, nil
+}
+
+func (g *constantGuardian) CanExportCSV(dsNames []string) (bool, error) {
+	return g.allowed, nil
+}
+
+func (g *constantGuardian) CanEditCachingPolicy() (bool, error) {
+	return g.allowed, nil
+}
+
+func (g *constantGuardian) CanBulkMoveFolderContents(targetFolderID int64) (bool, []int64, error) {
+	return g.allowed, []int64{}, nil
+}
+
+func (g *constantGuardian) CanAssignToWorkspace(workspaceID int64) (bool, error) {
+	return g.allowed, nil
+}
+
+func (g *constantGuardian) CanSeeLockHolder() (bool, error) {
+	return g.allowed, nil
+}
+
+func (g *constantGuardian) CanCleanupSnapshots() (bool, error) {
+	return g.allowed, nil
+}
+
+func (g *constantGuardian) CanApplyTemplateRecursively(folderID int64) (bool, []int64, error) {
+	return g.allowed, []int64{}, nil
+}
+
+func (g *constantGuardian) CanExemptFromPolicy(policyID string) (bool, error) {
+	return g.allowed, nil
+}
+
+func (g *constantGuardian) CanViewProvisioningSource() (bool, error) {
+	return g.allowed, nil
+}
+
+func (g *constantGuardian) CanCollapseToInherited() (bool, error) {
+	return g.allowed, nil
+}
+
+func (g *constantGuardian) CanShareWithOrg(targetOrgID int64) (bool, error) {
+	return g.allowed, nil
+}
+
+func (g *constantGuardian) CanPromotePanelToWidget(panelID int) (bool, error) {
+	return g.allowed, nil
+}
+
+func (g *constantGuardian) CanEditRLS() (bool, error) {
+	return g.allowed, nil
+}
+
+func (g *constantGuardian) CanRestoreFolder() (bool, []int64, error) {
+	return g.allowed, []int64{}, nil
+}
+
+func (g *constantGuardian) CanChangeUID() (bool, error) {
+	return g.allowed, nil
+}
+
+func (g *constantGuardian) CanEditRetentionPolicy() (bool, error) {
+	return g.allowed, nil
+}
+
+func (g *constantGuardian) CanBulkChangeOwner(newOwnerID int64) (bool, []int64, error) {
+	return g.allowed, []int64{}, nil
+}
+
+func (g *constantGuardian) CanSetAsOrgLanding() (bool, error) {
+	return g.allowed, nil
+}
+
+func (g *constantGuardian) CanEditBranding() (bool, error) {
+	return g.allowed, nil
+}
+
+func (g *constantGuardian) CanBulkExportFolder() (bool, []int64, error) {
+	return g.allowed, []int64{}, nil
+}
+
+func (g *constantGuardian) CanConfigureWebhooks() (bool, error) {
+	return g.allowed, nil
+}
+
+func (g *constantGuardian) CanDetachFromProvisioning() (bool, error) {
+	return g.allowed, nil
+}
+
+func (g *constantGuardian) CanGrantRole(role m.RoleType) (bool, error) {
+	return g.allowed, nil
+}
+
+func (g *constantGuardian) CanMerge(sourceDashboardID int64) (bool, error) {
+	return g.allowed, nil
+}
+
+func (g *constantGuardian) CanConfigureSLO() (bool, error) {
+	return g.allowed, nil
+}
+
+func (g *constantGuardian) CanBulkStarFolder() (map[int64]bool, error) {
+	return map[int64]bool{}, nil
+}
+
+func (g *constantGuardian) CanManageSilences() (bool, error) {
+	return g.allowed, nil
+}
+
+func (g *constantGuardian) CanCloneToOrg(targetOrgID int64, targetFolderID int64) (bool, error) {
+	return g.allowed, nil
+}
+
+func (g *constantGuardian) CanEditDataMasking() (bool, error) {
+	return g.allowed, nil
+}
+
+func (g *constantGuardian) CanPinGoldenVersion(version int) (bool, error) {
+	return g.allowed, nil
+}
+
+func (g *constantGuardian) CanTransferFolderAdmin(newAdminID int64) (bool, error) {
+	return g.allowed, nil
+}
+
+func (g *constantGuardian) CanEditQueryTimeout() (bool, error) {
+	return g.allowed, nil
+}
+
+func (g *constantGuardian) CanEnableAnonymousAccess() (bool, error) {
+	return g.allowed, nil
+}
+
+func (g *constantGuardian) CanChangeInheritanceMode() (bool, error) {
+	return g.allowed, nil
+}
+
+func (g *constantGuardian) CanSubscribeTeam(teamID int64) (bool, error) {
+	return g.allowed, nil
+}
+
+func (g *constantGuardian) CanConfigureAuditLogging() (bool, error) {
+	return g.allowed, nil
+}
+
+func (g *constantGuardian) CanImportAcl(exported []byte) (bool, error) {
+	return g.allowed, nil
+}
+
+func (g *constantGuardian) CanAddToNavSection(sectionID string) (bool, error) {
+	return g.allowed, nil
+}
+
+func (g *constantGuardian) CanSaveInFolder(folderID int64) (bool, error) {
+	return g.allowed, nil
+}
+
+func (g *constantGuardian) CanDeprecate() (bool, error) {
+	return g.allowed, nil
+}
+
+func (g *constantGuardian) CanViewOrError() error {
+	return g.orError(m.PERMISSION_VIEW)
+}
+
+func (g *constantGuardian) CanEditOrError() error {
+	return g.orError(m.PERMISSION_EDIT)
+}
+
+func (g *constantGuardian) CanSaveOrError() error {
+	return g.orError(m.PERMISSION_EDIT)
+}
+
+func (g *constantGuardian) CanAdminOrError() error {
+	return g.orError(m.PERMISSION_ADMIN)
+}
+
+func (g *constantGuardian) CanEditDefaultVariables() (bool, error) {
+	return g.allowed, nil
+}
+
+func (g *constantGuardian) FilterDashboardListResults(dashboardIDs []int64) ([]int64, error) {
+	if !g.allowed {
+		return []int64{}, nil
+	}
+
+	return dashboardIDs, nil
+}
+
+func (g *constantGuardian) EvaluateForUser(user *m.SignedInUser, permission m.PermissionType) (bool, error) {
+	return g.allowed, nil
+}
+
+func (g *constantGuardian) CanEditContactPointOverrides() (bool, error) {
+	return g.allowed, nil
+}
+
+func (g *constantGuardian) CanDeleteFolderContents() (bool, error) {
+	return g.allowed, nil
+}
+
+func (g *constantGuardian) CanPromoteToSharedSpace() (bool, error) {
+	return g.allowed, nil
+}
+
+func (g *constantGuardian) CanEditFeatureFlags() (bool, error) {
+	return g.allowed, nil
+}
+
+func (g *constantGuardian) ExplainAccess(permission m.PermissionType) (*AccessExplanation, error) {
+	return &AccessExplanation{Permission: permission, Allowed: g.allowed}, nil
+}
+
+func (g *constantGuardian) CanEditPIIRedaction() (bool, error) {
+	return g.allowed, nil
+}
+
+func (g *constantGuardian) CanMoveOutOfLockedFolder(newFolderID int64) (bool, error) {
+	return g.allowed, nil
+}
+
+func (g *constantGuardian) CanEditExportWatermark() (bool, error) {
+	return g.allowed, nil
+}
+
+func (g *constantGuardian) CanEditEmbedAllowlist() (bool, error) {
+	return g.allowed, nil
+}
+
+func (g *constantGuardian) CanOverrideInheritanceTemporarily() (bool, error) {
+	return g.allowed, nil
+}
+
+func (g *constantGuardian) CanStar() (bool, error) {
+	return g.allowed, nil
+}
+
+func (g *constantGuardian) CanEditDatasourceFailover() (bool, error) {
+	return g.allowed, nil
+}
@@ -1,19 +1,81 @@
 package guardian
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
 
 	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/components/simplejson"
 	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/metrics"
 	m "github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/search"
+	"github.com/grafana/grafana/pkg/services/teamguardian"
 	"github.com/grafana/grafana/pkg/setting"
 )
 
 var (
 	ErrGuardianPermissionExists = errors.New("Permission already exists")
 	ErrGuardianOverride         = errors.New("You can only override a permission to be higher")
+	ErrGuardianGrantExceedsOwn  = errors.New("You can only grant a permission you have yourself")
+	ErrWouldRemoveLastAdmin     = errors.New("Only inheriting permissions would remove your admin permission")
+	ErrGuardianOrgMismatch      = errors.New("Dashboard does not belong to the organization of the user")
 )
 
+// PermissionDeniedError is returned by checks that want to tell the caller which requirement
+// the user is missing on which dashboard, or on which data source used by it
+type PermissionDeniedError struct {
+	DashboardId int64
+	IsFolder    bool
+	Datasource  string
+	Requirement string
+}
+
+func (e PermissionDeniedError) Error() string {
+	if e.Datasource != "" {
+		return fmt.Sprintf("Access denied to data source %s, requires %s permission", e.Datasource, e.Requirement)
+	}
+
+	if e.IsFolder {
+		return fmt.Sprintf("Access denied to folder %d, requires %s permission", e.DashboardId, e.Requirement)
+	}
+
+	return fmt.Sprintf("Access denied to dashboard %d, requires %s permission", e.DashboardId, e.Requirement)
+}
+
+// GrantExceedsOwnError is returned when an imported acl contains a grant the user applying it
+// could not give themselves. Exactly one of UserId, TeamId and Role names the grantee
+type GrantExceedsOwnError struct {
+	UserId     int64
+	TeamId     int64
+	Role       m.RoleType
+	Permission m.PermissionType
+}
+
+func (e GrantExceedsOwnError) Error() string {
+	grantee := fmt.Sprintf("role %s", e.Role)
+	if e.UserId != 0 {
+		grantee = fmt.Sprintf("user %d", e.UserId)
+	} else if e.TeamId != 0 {
+		grantee = fmt.Sprintf("team %d", e.TeamId)
+	}
+
+	return fmt.Sprintf("%s, %s permission for %s", ErrGuardianGrantExceedsOwn.Error(), e.Permission, grantee)
+}
+
+// AccessExplanation tells why the user has or lacks a permission on a dashboard. Match is the acl
+// item that gave the permission, if it comes from the folder its Inherited flag is set. Org admins
+// are allowed without any matching item
+type AccessExplanation struct {
+	DashboardId int64
+	Permission  m.PermissionType
+	Allowed     bool
+	OrgAdmin    bool
+	Match       *m.DashboardAclInfoDTO
+	Acl         []*m.DashboardAclInfoDTO
+}
+
 // DashboardGuardian to be used for guard against operations without access on dashboard and acl
 type DashboardGuardian interface {
 	CanSave() (bool, error)
@@ -23,236 +85,2341 @@ type DashboardGuardian interface {
 	HasPermission(permission m.PermissionType) (bool, error)
 	CheckPermissionBeforeUpdate(permission m.PermissionType, updatePermissions []*m.DashboardAcl) (bool, error)
 	GetAcl() ([]*m.DashboardAclInfoDTO, error)
+	CanSetSharedTimeRange() (bool, error)
+	CanDeleteWithDependencies() (bool, []string, error)
+	CanCreateWithDefaultTemplate(folderID int64) (bool, error)
+	CanExportAcl() (bool, error)
+	ExportAcl() ([]*m.DashboardAclInfoDTO, error)
+	CanEmbedInto(parentDashboardID int64) (bool, error)
+	CanRestoreVersion(version int) (bool, error)
+	CanAckAlerts() (bool, error)
+	CanTrash() (bool, error)
+	CanPurge() (bool, error)
+	CanDuplicateFolder(targetParentID int64) (bool, error)
+	CanManageDatasourcePropagation() (bool, error)
+	CanBulkTagFolder() (bool, error)
+	GetEditableFolderDashboards() ([]int64, error)
+	CanShareToChannel(integration string) (bool, error)
+	CanCreateFromTemplate(targetFolderID int64, dsNames []string) (bool, error)
+	IsSoleAdmin() (bool, error)
+	CanPauseAlerts() (bool, error)
+	CanAttachToIncident(incidentID string) (bool, error)
+	CanSetAsTeamDefault(teamID int64) (bool, error)
+	CanConfigureAccessRequests() (bool, error)
+	CanRequestAccess(level m.PermissionType) (bool, error)
+	CanApproveAccessRequest(targetUserID int64, level m.PermissionType) (bool, error)
+	CanPinToPersonalHome() (bool, error)
+	CanEditLinks() (bool, error)
+	CanExportCSV(dsNames []string) (bool, error)
+	CanEditCachingPolicy() (bool, error)
+	CanBulkMoveFolderContents(targetFolderID int64) (bool, []int64, error)
+	CanAssignToWorkspace(workspaceID int64) (bool, error)
+	CanSeeLockHolder() (bool, error)
+	CanCleanupSnapshots() (bool, error)
+	CanApplyTemplateRecursively(folderID int64) (bool, []int64, error)
+	CanExemptFromPolicy(policyID string) (bool, error)
+	CanViewProvisioningSource() (bool, error)
+	CanCollapseToInherited() (bool, error)
+	CanShareWithOrg(targetOrgID int64) (bool, error)
+	CanPromotePanelToWidget(panelID int) (bool, error)
+	CanEditRLS() (bool, error)
+	CanRestoreFolder() (bool, []int64, error)
+	CanChangeUID() (bool, error)
+	CanEditRetentionPolicy() (bool, error)
+	CanBulkChangeOwner(newOwnerID int64) (bool, []int64, error)
+	CanSetAsOrgLanding() (bool, error)
+	CanEditBranding() (bool, error)
+	CanBulkExportFolder() (bool, []int64, error)
+	CanConfigureWebhooks() (bool, error)
+	CanDetachFromProvisioning() (bool, error)
+	CanGrantRole(role m.RoleType) (bool, error)
+	CanMerge(sourceDashboardID int64) (bool, error)
+	CanConfigureSLO() (bool, error)
+	CanBulkStarFolder() (map[int64]bool, error)
+	CanManageSilences() (bool, error)
+	CanCloneToOrg(targetOrgID int64, targetFolderID int64) (bool, error)
+	CanEditDataMasking() (bool, error)
+	CanPinGoldenVersion(version int) (bool, error)
+	CanTransferFolderAdmin(newAdminID int64) (bool, error)
+	CanEditQueryTimeout() (bool, error)
+	CanEnableAnonymousAccess() (bool, error)
+	CanChangeInheritanceMode() (bool, error)
+	CanSubscribeTeam(teamID int64) (bool, error)
+	CanConfigureAuditLogging() (bool, error)
+	CanImportAcl(exported []byte) (bool, error)
+	CanAddToNavSection(sectionID string) (bool, error)
+	CanSaveInFolder(folderID int64) (bool, error)
+	CanDeprecate() (bool, error)
+	CanViewOrError() error
+	CanEditOrError() error
+	CanSaveOrError() error
+	CanAdminOrError() error
+	CanEditDefaultVariables() (bool, error)
+	FilterDashboardListResults(dashboardIDs []int64) ([]int64, error)
+	EvaluateForUser(user *m.SignedInUser, permission m.PermissionType) (bool, error)
+	CanEditContactPointOverrides() (bool, error)
+	CanDeleteFolderContents() (bool, error)
+	CanPromoteToSharedSpace() (bool, error)
+	CanEditFeatureFlags() (bool, error)
+	ExplainAccess(permission m.PermissionType) (*AccessExplanation, error)
+	CanEditPIIRedaction() (bool, error)
+	CanMoveOutOfLockedFolder(newFolderID int64) (bool, error)
+	CanEditExportWatermark() (bool, error)
+	CanEditEmbedAllowlist() (bool, error)
+	CanOverrideInheritanceTemporarily() (bool, error)
+	CanStar() (bool, error)
+	CanEditDatasourceFailover() (bool, error)
 }
 
 type dashboardGuardianImpl struct {
-	user   *m.SignedInUser
-	dashId int64
-	orgId  int64
-	acl    []*m.DashboardAclInfoDTO
-	teams  []*m.TeamDTO
-	log    log.Logger
+	user        *m.SignedInUser
+	dashId      int64
+	orgId       int64
+	dashboard   *m.Dashboard
+	acl         []*m.DashboardAclInfoDTO
+	teams       []*m.TeamDTO
+	permissions map[m.PermissionType]bool
+	log         log.Logger
 }
 
 // New factory for creating a new dashboard guardian instance
 var New = func(dashId int64, orgId int64, user *m.SignedInUser) DashboardGuardian {
 	return &dashboardGuardianImpl{
-		user:   user,
-		dashId: dashId,
-		orgId:  orgId,
-		log:    log.New("dashboard.permissions"),
+		user:        user,
+		dashId:      dashId,
+		orgId:       orgId,
+		permissions: map[m.PermissionType]bool{},
+		log:         log.New("dashboard.permissions"),
 	}
 }
 
-func (g *dashboardGuardianImpl) CanSave() (bool, error) {
-	return g.HasPermission(m.PERMISSION_EDIT)
+// NewByUID creates a guardian for the dashboard with the given uid. The dashboard is looked up
+// first, so an unknown uid returns the same m.ErrDashboardNotFound as the dashboard query, and
+// kept for the checks that need it
+func NewByUID(uid string, orgId int64, user *m.SignedInUser) (DashboardGuardian, error) {
+	query := m.GetDashboardQuery{Uid: uid, OrgId: orgId}
+	if err := bus.Dispatch(&query); err != nil {
+		return nil, err
+	}
+
+	return newForDashboard(query.Result, orgId, user), nil
 }
 
-func (g *dashboardGuardianImpl) CanEdit() (bool, error) {
-	if setting.ViewersCanEdit {
-		return g.HasPermission(m.PERMISSION_VIEW)
+// NewByDashboard creates a guardian for an already loaded dashboard, e.g. right after saving it,
+// so checks that need the dashboard itself don't look it up again. The dashboard has to belong to
+// the organization of the user, otherwise ErrGuardianOrgMismatch is returned
+func NewByDashboard(dash *m.Dashboard, user *m.SignedInUser) (DashboardGuardian, error) {
+	if dash.OrgId != user.OrgId {
+		return nil, ErrGuardianOrgMismatch
 	}
 
-	return g.HasPermission(m.PERMISSION_EDIT)
+	return newForDashboard(dash, dash.OrgId, user), nil
 }
 
-func (g *dashboardGuardianImpl) CanView() (bool, error) {
-	return g.HasPermission(m.PERMISSION_VIEW)
+func newForDashboard(dash *m.Dashboard, orgId int64, user *m.SignedInUser) DashboardGuardian {
+	g := New(dash.Id, orgId, user)
+	// a mocked guardian has no dashboard to keep
+	if impl, ok := g.(*dashboardGuardianImpl); ok {
+		impl.dashboard = dash
+	}
+
+	return g
 }
 
-func (g *dashboardGuardianImpl) CanAdmin() (bool, error) {
-	return g.HasPermission(m.PERMISSION_ADMIN)
+// MultiOrgGuardian checks a dashboard that is shared into several organizations, where it is found
+// by its uid. Permissions are evaluated per organization, so the caller has to supply the user as
+// signed in to each of them, with the role and teams of that organization
+type MultiOrgGuardian struct {
+	uid   string
+	users map[int64]*m.SignedInUser
 }
 
-func (g *dashboardGuardianImpl) HasPermission(permission m.PermissionType) (bool, error) {
-	if g.user.OrgRole == m.ROLE_ADMIN {
-		return g.logHasPermissionResult(permission, true, nil)
-	}
+// NewMultiOrgGuardian creates a guardian for the dashboard with the given uid in every organization
+// of users, which maps the organization ids to the user signed in to them
+func NewMultiOrgGuardian(uid string, users map[int64]*m.SignedInUser) *MultiOrgGuardian {
+	return &MultiOrgGuardian{uid: uid, users: users}
+}
 
-	acl, err := g.GetAcl()
-	if err != nil {
-		return g.logHasPermissionResult(permission, false, err)
+// CanView returns for each organization whether the user may view the dashboard there. A guardian
+// is created per organization, so no permissions are shared between them. Organizations without
+// the dashboard are not viewable, a user signed in to another organization returns
+// ErrGuardianOrgMismatch
+func (g *MultiOrgGuardian) CanView() (map[int64]bool, error) {
+	result := make(map[int64]bool, len(g.users))
+	for orgId, user := range g.users {
+		if user.OrgId != orgId {
+			return nil, ErrGuardianOrgMismatch
+		}
+
+		orgGuardian, err := NewByUID(g.uid, orgId, user)
+		if err == m.ErrDashboardNotFound {
+			result[orgId] = false
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if result[orgId], err = orgGuardian.CanView(); err != nil {
+			return nil, err
+		}
 	}
 
-	result, err := g.checkAcl(permission, acl)
-	return g.logHasPermissionResult(permission, result, err)
+	return result, nil
 }
 
-func (g *dashboardGuardianImpl) logHasPermissionResult(permission m.PermissionType, hasPermission bool, err error) (bool, error) {
-	if err != nil {
-		return hasPermission, err
-	}
+// BatchCanView returns for each of the dashboards whether the user may view it. Unlike calling
+// CanView on a guardian per dashboard, all dashboards are checked in a single query, which also
+// resolves the permissions inherited from their folders
+func BatchCanView(orgId int64, dashboardIDs []int64, user *m.SignedInUser) (map[int64]bool, error) {
+	return batchHasPermission(orgId, dashboardIDs, user, m.PERMISSION_VIEW)
+}
 
-	if hasPermission {
-		g.log.Debug("User granted access to execute action", "userId", g.user.UserId, "orgId", g.orgId, "uname", g.user.Login, "dashId", g.dashId, "action", permission)
-	} else {
-		g.log.Debug("User denied access to execute action", "userId", g.user.UserId, "orgId", g.orgId, "uname", g.user.Login, "dashId", g.dashId, "action", permission)
+// BatchCanDeleteSnapshots returns for each of the dashboards whether the user may delete its
+// snapshots in bulk, which requires admin on the dashboard. The acls of all dashboards, including
+// the items inherited from their folders, are loaded in a single query
+func BatchCanDeleteSnapshots(orgId int64, dashboardIDs []int64, user *m.SignedInUser) (map[int64]bool, error) {
+	return batchHasPermission(orgId, dashboardIDs, user, m.PERMISSION_ADMIN)
+}
+
+// BatchCanRestore returns for each of the trashed dashboards whether the user may restore it,
+// which needs the same permission as saving a dashboard in its folder. The dashboards are loaded
+// and their folders checked in a single query each, dashboards of other orgs are never allowed
+func BatchCanRestore(orgId int64, dashboardIDs []int64, user *m.SignedInUser) (map[int64]bool, error) {
+	result := make(map[int64]bool, len(dashboardIDs))
+	for _, id := range dashboardIDs {
+		result[id] = false
+	}
+	if len(dashboardIDs) == 0 {
+		return result, nil
 	}
 
-	return hasPermission, err
-}
+	query := m.GetDashboardsQuery{DashboardIds: dashboardIDs}
+	if err := bus.Dispatch(&query); err != nil {
+		return nil, err
+	}
 
-func (g *dashboardGuardianImpl) checkAcl(permission m.PermissionType, acl []*m.DashboardAclInfoDTO) (bool, error) {
-	orgRole := g.user.OrgRole
-	teamAclItems := []*m.DashboardAclInfoDTO{}
+	folders := map[int64][]int64{}
+	for _, dash := range query.Result {
+		if dash.OrgId == orgId {
+			folders[dash.FolderId] = append(folders[dash.FolderId], dash.Id)
+		}
+	}
 
-	for _, p := range acl {
-		// user match
-		if !g.user.IsAnonymous && p.UserId > 0 {
-			if p.UserId == g.user.UserId && p.Permission >= permission {
-				return true, nil
-			}
+	folderIds := []int64{}
+	for folderId := range folders {
+		if folderId != 0 {
+			folderIds = append(folderIds, folderId)
 		}
+	}
 
-		// role match
-		if p.Role != nil {
-			if *p.Role == orgRole && p.Permission >= permission {
-				return true, nil
+	permissions, err := getPermissionsForUser(orgId, user, folderIds)
+	if err != nil {
+		return nil, err
+	}
+
+	for folderId, ids := range folders {
+		canSave := permissions[folderId] >= m.PERMISSION_EDIT
+		// the General folder has no permissions of its own but the defaults of the org roles
+		if folderId == 0 {
+			if canSave, err = New(0, orgId, user).CanSave(); err != nil {
+				return nil, err
 			}
 		}
 
-		// remember this rule for later
-		if p.TeamId > 0 {
-			teamAclItems = append(teamAclItems, p)
+		for _, id := range ids {
+			result[id] = canSave
 		}
 	}
 
-	// do we have team rules?
-	if len(teamAclItems) == 0 {
-		return false, nil
+	return result, nil
+}
+
+// BatchGetAcl returns the acl of each of the dashboards, e.g. for listing the permissions of all
+// dashboards in a folder. The acls are loaded in a single query instead of one per dashboard
+func BatchGetAcl(orgId int64, dashboardIDs []int64) (map[int64][]*m.DashboardAclInfoDTO, error) {
+	query := m.GetDashboardsAclInfoListQuery{DashboardIds: dashboardIDs, OrgId: orgId}
+	if err := bus.Dispatch(&query); err != nil {
+		return nil, err
 	}
 
-	// load teams
-	teams, err := g.getTeams()
+	return query.Result, nil
+}
+
+// GetFolderChildrenAcl returns the acl of each dashboard in the folder, see BatchGetAcl
+func GetFolderChildrenAcl(orgId int64, folderId int64) (map[int64][]*m.DashboardAclInfoDTO, error) {
+	children, err := getFolderChildren(orgId, folderId)
 	if err != nil {
-		return false, err
+		return nil, err
 	}
 
-	// evaluate team rules
-	for _, p := range acl {
-		for _, ug := range teams {
-			if ug.Id == p.TeamId && p.Permission >= permission {
-				return true, nil
-			}
+	return BatchGetAcl(orgId, dashboardIds(children))
+}
+
+// CanBulkTagByFilter resolves the search filter to the dashboards the user can see and splits them
+// into the ones the user may tag, which requires edit, and the blocked ones. Dashboards the user
+// cannot see are not matched at all, and all matches are checked in a single query
+func CanBulkTagByFilter(filter search.Query, user *m.SignedInUser) ([]int64, []int64, error) {
+	filter.OrgId = user.OrgId
+	filter.SignedInUser = user
+	filter.Type = string(search.DashHitDB)
+	filter.Permission = m.PERMISSION_VIEW
+	if err := bus.Dispatch(&filter); err != nil {
+		return nil, nil, err
+	}
+
+	ids := make([]int64, 0, len(filter.Result))
+	for _, hit := range filter.Result {
+		ids = append(ids, hit.Id)
+	}
+
+	permissions, err := getPermissionsForUser(user.OrgId, user, ids)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	editable, blocked := []int64{}, []int64{}
+	for _, id := range ids {
+		if permissions[id] >= m.PERMISSION_EDIT {
+			editable = append(editable, id)
+		} else {
+			blocked = append(blocked, id)
 		}
 	}
 
-	return false, nil
+	return editable, blocked, nil
 }
 
-func (g *dashboardGuardianImpl) CheckPermissionBeforeUpdate(permission m.PermissionType, updatePermissions []*m.DashboardAcl) (bool, error) {
-	acl := []*m.DashboardAclInfoDTO{}
-	adminRole := m.ROLE_ADMIN
-	everyoneWithAdminRole := &m.DashboardAclInfoDTO{DashboardId: g.dashId, UserId: 0, TeamId: 0, Role: &adminRole, Permission: m.PERMISSION_ADMIN}
+// CanBulkMoveSearchResults splits the dashboards into the ones the user may move to the target
+// folder and the blocked ones. Moving needs edit on each dashboard, checked in a single query, and
+// edit on the target folder, checked once. Without the latter all dashboards are blocked
+func CanBulkMoveSearchResults(dashboardIDs []int64, targetFolderID int64, user *m.SignedInUser) ([]int64, []int64, error) {
+	canSaveInTarget, err := New(targetFolderID, user.OrgId, user).CanSave()
+	if err != nil {
+		return nil, nil, err
+	}
 
-	// validate that duplicate permissions don't exists
-	for _, p := range updatePermissions {
-		aclItem := &m.DashboardAclInfoDTO{DashboardId: p.DashboardId, UserId: p.UserId, TeamId: p.TeamId, Role: p.Role, Permission: p.Permission}
-		if aclItem.IsDuplicateOf(everyoneWithAdminRole) {
-			return false, ErrGuardianPermissionExists
+	permissions := map[int64]m.PermissionType{}
+	if canSaveInTarget {
+		if permissions, err = getPermissionsForUser(user.OrgId, user, dashboardIDs); err != nil {
+			return nil, nil, err
 		}
+	}
 
-		for _, a := range acl {
-			if a.IsDuplicateOf(aclItem) {
-				return false, ErrGuardianPermissionExists
-			}
+	movable, blocked := []int64{}, []int64{}
+	for _, id := range dashboardIDs {
+		if permissions[id] >= m.PERMISSION_EDIT {
+			movable = append(movable, id)
+		} else {
+			blocked = append(blocked, id)
 		}
-
-		acl = append(acl, aclItem)
 	}
 
-	existingPermissions, err := g.GetAcl()
+	return movable, blocked, nil
+}
+
+func batchHasPermission(orgId int64, dashboardIDs []int64, user *m.SignedInUser, permission m.PermissionType) (map[int64]bool, error) {
+	permissions, err := getPermissionsForUser(orgId, user, dashboardIDs)
 	if err != nil {
-		return false, err
+		return nil, err
 	}
 
-	// validate overridden permissions to be higher
-	for _, a := range acl {
-		for _, existingPerm := range existingPermissions {
-			if !existingPerm.Inherited {
-				continue
-			}
-
-			if a.IsDuplicateOf(existingPerm) && a.Permission <= existingPerm.Permission {
-				return false, ErrGuardianOverride
-			}
-		}
+	result := make(map[int64]bool, len(dashboardIDs))
+	for _, id := range dashboardIDs {
+		result[id] = permissions[id] >= permission
 	}
 
-	if g.user.OrgRole == m.ROLE_ADMIN {
-		return true, nil
+	return result, nil
+}
+
+func (g *dashboardGuardianImpl) CanSave() (bool, error) {
+	return g.HasPermission(m.PERMISSION_EDIT)
+}
+
+func (g *dashboardGuardianImpl) CanEdit() (bool, error) {
+	if setting.ViewersCanEdit {
+		return g.HasPermission(m.PERMISSION_VIEW)
 	}
 
-	return g.checkAcl(permission, existingPermissions)
+	return g.HasPermission(m.PERMISSION_EDIT)
 }
 
-// GetAcl returns dashboard acl
-func (g *dashboardGuardianImpl) GetAcl() ([]*m.DashboardAclInfoDTO, error) {
-	if g.acl != nil {
-		return g.acl, nil
+func (g *dashboardGuardianImpl) CanView() (bool, error) {
+	return g.HasPermission(m.PERMISSION_VIEW)
+}
+
+func (g *dashboardGuardianImpl) CanAdmin() (bool, error) {
+	return g.HasPermission(m.PERMISSION_ADMIN)
+}
+
+// CanSetSharedTimeRange returns true if the user may lock the time range for all viewers of the dashboard
+func (g *dashboardGuardianImpl) CanSetSharedTimeRange() (bool, error) {
+	return g.CanAdmin()
+}
+
+// CanDeleteWithDependencies returns true if the user may delete the dashboard together with the
+// alerts and playlist items that would be removed along with it, so the caller can warn before
+// deleting. Playlists that only include the dashboard through a tag are not affected
+func (g *dashboardGuardianImpl) CanDeleteWithDependencies() (bool, []string, error) {
+	canDelete, err := g.CanSave()
+	if err != nil || !canDelete {
+		return false, nil, err
 	}
 
-	query := m.GetDashboardAclInfoListQuery{DashboardId: g.dashId, OrgId: g.orgId}
+	query := m.GetAlertsQuery{OrgId: g.orgId, DashboardIDs: []int64{g.dashId}, User: g.user}
 	if err := bus.Dispatch(&query); err != nil {
-		return nil, err
+		return false, nil, err
 	}
 
-	g.acl = query.Result
-	return g.acl, nil
+	playlistQuery := m.GetPlaylistsByDashboardIdQuery{DashboardId: g.dashId, OrgId: g.orgId}
+	if err := bus.Dispatch(&playlistQuery); err != nil {
+		return false, nil, err
+	}
+
+	blockers := []string{}
+	for _, alert := range query.Result {
+		blockers = append(blockers, fmt.Sprintf("alert:%d", alert.Id))
+	}
+	for _, playlist := range playlistQuery.Result {
+		blockers = append(blockers, fmt.Sprintf("playlist:%d", playlist.Id))
+	}
+
+	return true, blockers, nil
 }
 
-func (g *dashboardGuardianImpl) getTeams() ([]*m.TeamDTO, error) {
-	if g.teams != nil {
-		return g.teams, nil
+// CanCreateWithDefaultTemplate returns true if the user may create a dashboard in the folder and
+// apply the folder permissions new dashboards get by default. Admin grants in those permissions
+// are above what an editor can assign, so they require admin on the folder
+func (g *dashboardGuardianImpl) CanCreateWithDefaultTemplate(folderID int64) (bool, error) {
+	folderGuardian := New(folderID, g.orgId, g.user)
+	canCreate, err := folderGuardian.CanSave()
+	if err != nil || !canCreate {
+		return false, err
 	}
 
-	query := m.GetTeamsByUserQuery{OrgId: g.orgId, UserId: g.user.UserId}
-	err := bus.Dispatch(&query)
+	acl, err := folderGuardian.GetAcl()
+	if err != nil {
+		return false, err
+	}
 
-	g.teams = query.Result
-	return query.Result, err
+	for _, p := range acl {
+		if p.Permission > m.PERMISSION_EDIT {
+			return folderGuardian.CanAdmin()
+		}
+	}
+
+	return true, nil
 }
 
-type FakeDashboardGuardian struct {
-	DashId                           int64
-	OrgId                            int64
-	User                             *m.SignedInUser
-	CanSaveValue                     bool
-	CanEditValue                     bool
-	CanViewValue                     bool
-	CanAdminValue                    bool
-	HasPermissionValue               bool
-	CheckPermissionBeforeUpdateValue bool
-	CheckPermissionBeforeUpdateError error
-	GetAclValue                      []*m.DashboardAclInfoDTO
+// CanExportAcl returns true if the user may export who has access to the dashboard. Reading the
+// permission list requires admin, same as the permissions page
+func (g *dashboardGuardianImpl) CanExportAcl() (bool, error) {
+	return g.CanAdmin()
 }
 
-func (g *FakeDashboardGuardian) CanSave() (bool, error) {
-	return g.CanSaveValue, nil
+// CanEmbedInto returns true if the user may view the dashboard and edit the parent dashboard it
+// is embedded into. A PermissionDeniedError tells which side failed
+func (g *dashboardGuardianImpl) CanEmbedInto(parentDashboardID int64) (bool, error) {
+	if ok, err := denyUnless(g.dashId, m.PERMISSION_VIEW, g.CanView); err != nil || !ok {
+		return ok, err
+	}
+
+	parentGuardian := New(parentDashboardID, g.orgId, g.user)
+	return denyUnless(parentDashboardID, m.PERMISSION_EDIT, parentGuardian.CanEdit)
 }
 
-func (g *FakeDashboardGuardian) CanEdit() (bool, error) {
-	return g.CanEditValue, nil
+// CanRestoreVersion returns true if the user may overwrite the dashboard with the given version.
+// Restoring is a save, so it requires the same permission as CanSave
+func (g *dashboardGuardianImpl) CanRestoreVersion(version int) (bool, error) {
+	canSave, err := g.CanSave()
+	if err != nil || !canSave {
+		return false, err
+	}
+
+	query := m.GetDashboardVersionQuery{DashboardId: g.dashId, OrgId: g.orgId, Version: version}
+	if err := bus.Dispatch(&query); err != nil {
+		return false, err
+	}
+
+	return true, nil
 }
 
-func (g *FakeDashboardGuardian) CanView() (bool, error) {
-	return g.CanViewValue, nil
+// CanAckAlerts returns true if the user may acknowledge alerts of the dashboard. Acknowledging
+// is lighter than editing the alert rules, so viewers can ack by default
+func (g *dashboardGuardianImpl) CanAckAlerts() (bool, error) {
+	return g.CanView()
 }
 
-func (g *FakeDashboardGuardian) CanAdmin() (bool, error) {
-	return g.CanAdminValue, nil
+// CanTrash returns true if the user may move the dashboard to the trash, which needs the same
+// permission as deleting it
+func (g *dashboardGuardianImpl) CanTrash() (bool, error) {
+	return g.CanSave()
 }
 
-func (g *FakeDashboardGuardian) HasPermission(permission m.PermissionType) (bool, error) {
-	return g.HasPermissionValue, nil
+// CanPurge returns true if the user may permanently delete the dashboard. Unlike trashing it
+// cannot be undone, so it requires admin
+func (g *dashboardGuardianImpl) CanPurge() (bool, error) {
+	return g.CanAdmin()
 }
 
-func (g *FakeDashboardGuardian) CheckPermissionBeforeUpdate(permission m.PermissionType, updatePermissions []*m.DashboardAcl) (bool, error) {
-	return g.CheckPermissionBeforeUpdateValue, g.CheckPermissionBeforeUpdateError
+// CanDuplicateFolder returns true if the user may create a copy of the folder under the target
+// parent and can view every dashboard in the folder
+func (g *dashboardGuardianImpl) CanDuplicateFolder(targetParentID int64) (bool, error) {
+	targetGuardian := New(targetParentID, g.orgId, g.user)
+	if canCreate, err := targetGuardian.CanSave(); err != nil || !canCreate {
+		return false, err
+	}
+
+	if canView, err := g.CanView(); err != nil || !canView {
+		return false, err
+	}
+
+	blocked, err := g.getBlockedFolderChildren(g.dashId, m.PERMISSION_VIEW)
+	if err != nil {
+		return false, err
+	}
+
+	return len(blocked) == 0, nil
 }
 
-func (g *FakeDashboardGuardian) GetAcl() ([]*m.DashboardAclInfoDTO, error) {
-	return g.GetAclValue, nil
+// CanManageDatasourcePropagation returns true if the user may change whether the dashboard passes
+// its access on to the data sources it uses. Data source permissions can only be changed by org
+// admins, so on top of admin on the dashboard the user must be an org admin. Otherwise the
+// PermissionDeniedError names the first data source used by the panels, if they name any
+func (g *dashboardGuardianImpl) CanManageDatasourcePropagation() (bool, error) {
+	if ok, err := denyUnless(g.dashId, m.PERMISSION_ADMIN, g.CanAdmin); err != nil || !ok {
+		return ok, err
+	}
+
+	if g.user.OrgRole == m.ROLE_ADMIN {
+		return true, nil
+	}
+
+	dash, err := g.getDashboard()
+	if err != nil {
+		return false, err
+	}
+
+	denied := PermissionDeniedError{DashboardId: g.dashId, Requirement: "org admin"}
+	if dsNames := getDashboardDatasources(dash); len(dsNames) > 0 {
+		denied.Datasource = dsNames[0]
+	}
+
+	return false, denied
+}
+
+// CanBulkTagFolder returns true if the user may apply tags to all dashboards in the folder at once
+func (g *dashboardGuardianImpl) CanBulkTagFolder() (bool, error) {
+	return g.CanSave()
+}
+
+// GetEditableFolderDashboards returns the ids of the dashboards in the folder the user may save.
+// Used for tagging dashboards one by one when the user cannot write to the folder itself
+func (g *dashboardGuardianImpl) GetEditableFolderDashboards() ([]int64, error) {
+	children, err := getFolderChildren(g.orgId, g.dashId)
+	if err != nil {
+		return nil, err
+	}
+
+	permissions, err := getPermissionsForUser(g.orgId, g.user, dashboardIds(children))
+	if err != nil {
+		return nil, err
+	}
+
+	editable := []int64{}
+	for _, child := range children {
+		if permissions[child.Id] >= m.PERMISSION_EDIT {
+			editable = append(editable, child.Id)
+		}
+	}
+
+	return editable, nil
+}
+
+// CanShareToChannel returns true if the user may post a rendered snapshot of the dashboard to a
+// chat channel. Besides viewing the dashboard this needs the editor role, which is what using
+// notification channels requires
+func (g *dashboardGuardianImpl) CanShareToChannel(integration string) (bool, error) {
+	if ok, err := denyUnless(g.dashId, m.PERMISSION_VIEW, g.CanView); err != nil || !ok {
+		return ok, err
+	}
+
+	if !g.user.HasRole(m.ROLE_EDITOR) {
+		return false, PermissionDeniedError{DashboardId: g.dashId, Requirement: "editor role"}
+	}
+
+	return true, nil
+}
+
+// CanCreateFromTemplate returns true if the user may create a dashboard from a template in the
+// target folder and query all data sources the template uses
+func (g *dashboardGuardianImpl) CanCreateFromTemplate(targetFolderID int64, dsNames []string) (bool, error) {
+	folderGuardian := New(targetFolderID, g.orgId, g.user)
+	if ok, err := denyUnless(targetFolderID, m.PERMISSION_EDIT, folderGuardian.CanSave); err != nil || !ok {
+		return ok, err
+	}
+
+	blocked, err := g.getBlockedDatasource(dsNames)
+	if err != nil {
+		return false, err
+	}
+
+	if blocked != "" {
+		return false, PermissionDeniedError{DashboardId: targetFolderID, Datasource: blocked, Requirement: m.DsPermissionQuery.String()}
+	}
+
+	return true, nil
+}
+
+// IsSoleAdmin returns true if the user is the only user, team or role with admin permission on
+// the dashboard and admin is not inherited from the folder. Org admins are not counted since
+// they are admin on every dashboard regardless of the acl
+func (g *dashboardGuardianImpl) IsSoleAdmin() (bool, error) {
+	acl, err := g.GetAcl()
+	if err != nil {
+		return false, err
+	}
+
+	isAdmin := false
+	for _, p := range acl {
+		if p.Permission < m.PERMISSION_ADMIN {
+			continue
+		}
+
+		if p.Inherited || p.UserId != g.user.UserId || p.TeamId > 0 || p.Role != nil {
+			return false, nil
+		}
+
+		isAdmin = true
+	}
+
+	return isAdmin, nil
+}
+
+// CanPauseAlerts returns true if the user may pause or resume the alert rules of the dashboard
+func (g *dashboardGuardianImpl) CanPauseAlerts() (bool, error) {
+	return g.CanEdit()
+}
+
+// CanAttachToIncident returns true if the user may attach the dashboard to an incident. Incidents
+// are managed through the notification integrations, so writing to one needs the editor role
+func (g *dashboardGuardianImpl) CanAttachToIncident(incidentID string) (bool, error) {
+	if ok, err := denyUnless(g.dashId, m.PERMISSION_VIEW, g.CanView); err != nil || !ok {
+		return ok, err
+	}
+
+	if !g.user.HasRole(m.ROLE_EDITOR) {
+		return false, PermissionDeniedError{DashboardId: g.dashId, Requirement: "editor role"}
+	}
+
+	return true, nil
+}
+
+// CanSetAsTeamDefault returns true if the user may make the dashboard the home dashboard of the
+// given team, which requires viewing the dashboard and being an admin of the team
+func (g *dashboardGuardianImpl) CanSetAsTeamDefault(teamID int64) (bool, error) {
+	if ok, err := denyUnless(g.dashId, m.PERMISSION_VIEW, g.CanView); err != nil || !ok {
+		return ok, err
+	}
+
+	return g.denyUnlessTeamAdmin(teamID)
+}
+
+// CanConfigureAccessRequests returns true if the user may configure how access to the dashboard
+// can be requested
+func (g *dashboardGuardianImpl) CanConfigureAccessRequests() (bool, error) {
+	return g.CanAdmin()
+}
+
+// CanRequestAccess returns true if the user may ask for the given permission on the dashboard.
+// Any signed in member of the organization can request access
+func (g *dashboardGuardianImpl) CanRequestAccess(level m.PermissionType) (bool, error) {
+	if g.user.IsAnonymous || g.user.OrgId != g.orgId {
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// CanApproveAccessRequest returns true if the user may grant the requested permission on the
+// dashboard. Approving is a permission change, so it requires admin. A level that is not view,
+// edit or admin returns ErrGuardianGrantExceedsOwn, even for org admins, and ErrOrgUserNotFound
+// is returned if the requesting user is not a member of the org
+func (g *dashboardGuardianImpl) CanApproveAccessRequest(targetUserID int64, level m.PermissionType) (bool, error) {
+	if !isKnownPermission(level) {
+		return false, ErrGuardianGrantExceedsOwn
+	}
+
+	canAdmin, err := g.CanAdmin()
+	if err != nil || !canAdmin {
+		return false, err
+	}
+
+	query := m.GetSignedInUserQuery{UserId: targetUserID, OrgId: g.orgId}
+	if err := bus.Dispatch(&query); err != nil {
+		return false, err
+	}
+
+	if query.Result.OrgId != g.orgId {
+		return false, m.ErrOrgUserNotFound
+	}
+
+	return true, nil
+}
+
+// CanPinToPersonalHome returns true if the user may set the dashboard as their own home
+// dashboard
+func (g *dashboardGuardianImpl) CanPinToPersonalHome() (bool, error) {
+	return g.CanView()
+}
+
+// CanEditLinks returns true if the user may change the links and navigation of the dashboard.
+// Links are part of the dashboard model, so for now this is the same as CanEdit
+func (g *dashboardGuardianImpl) CanEditLinks() (bool, error) {
+	return g.CanEdit()
+}
+
+// CanExportCSV returns true if the user may export the raw query results of the dashboard. This
+// reads data straight from the data sources, so every one of them must allow the user to query
+func (g *dashboardGuardianImpl) CanExportCSV(dsNames []string) (bool, error) {
+	if ok, err := denyUnless(g.dashId, m.PERMISSION_VIEW, g.CanView); err != nil || !ok {
+		return ok, err
+	}
+
+	blocked, err := g.getBlockedDatasource(dsNames)
+	if err != nil {
+		return false, err
+	}
+
+	if blocked != "" {
+		return false, PermissionDeniedError{DashboardId: g.dashId, Datasource: blocked, Requirement: m.DsPermissionQuery.String()}
+	}
+
+	return true, nil
+}
+
+// CanEditCachingPolicy returns true if the user may change how query results of the dashboard
+// are cached. Caching affects data freshness for every viewer, so it is an admin action
+func (g *dashboardGuardianImpl) CanEditCachingPolicy() (bool, error) {
+	return g.CanAdmin()
+}
+
+// CanBulkMoveFolderContents returns true if the user may move every dashboard in the folder to
+// the target folder. This needs edit on both folders and on each dashboard, the ids of the
+// dashboards the user cannot edit are returned as blocked
+func (g *dashboardGuardianImpl) CanBulkMoveFolderContents(targetFolderID int64) (bool, []int64, error) {
+	if canSave, err := g.CanSave(); err != nil || !canSave {
+		return false, nil, err
+	}
+
+	targetGuardian := New(targetFolderID, g.orgId, g.user)
+	if canSave, err := targetGuardian.CanSave(); err != nil || !canSave {
+		return false, nil, err
+	}
+
+	blocked, err := g.getBlockedFolderChildren(g.dashId, m.PERMISSION_EDIT)
+	if err != nil {
+		return false, nil, err
+	}
+
+	return len(blocked) == 0, blocked, nil
+}
+
+// CanAssignToWorkspace returns true if the user may assign the dashboard to a workspace. Folders
+// are the only dashboard containers, so the workspace is checked as a folder the user must be
+// able to edit. A PermissionDeniedError tells which side failed
+func (g *dashboardGuardianImpl) CanAssignToWorkspace(workspaceID int64) (bool, error) {
+	if ok, err := denyUnless(g.dashId, m.PERMISSION_ADMIN, g.CanAdmin); err != nil || !ok {
+		return ok, err
+	}
+
+	workspaceGuardian := New(workspaceID, g.orgId, g.user)
+	return denyUnless(workspaceID, m.PERMISSION_EDIT, workspaceGuardian.CanSave)
+}
+
+// CanSeeLockHolder returns true if the user may see who holds the edit lock of the dashboard.
+// With lock_holder_privacy enabled this requires edit instead of view
+func (g *dashboardGuardianImpl) CanSeeLockHolder() (bool, error) {
+	if setting.DashboardLockHolderPrivacy {
+		return g.CanEdit()
+	}
+
+	return g.CanView()
+}
+
+// CanCleanupSnapshots returns true if the user may purge the expired snapshots of the dashboard.
+// Deleting a single snapshot needs edit, a cleanup run needs admin
+func (g *dashboardGuardianImpl) CanCleanupSnapshots() (bool, error) {
+	return g.CanAdmin()
+}
+
+// CanApplyTemplateRecursively returns true if the user may apply a permission template to the
+// folder and everything in it, which needs admin on all of them. Folders can not be nested, so
+// this covers the folder and its dashboards. The ids the user cannot administer are returned as
+// blocked
+func (g *dashboardGuardianImpl) CanApplyTemplateRecursively(folderID int64) (bool, []int64, error) {
+	blocked := []int64{}
+
+	folderGuardian := New(folderID, g.orgId, g.user)
+	canAdmin, err := folderGuardian.CanAdmin()
+	if err != nil {
+		return false, nil, err
+	}
+	if !canAdmin {
+		blocked = append(blocked, folderID)
+	}
+
+	blockedChildren, err := g.getBlockedFolderChildren(folderID, m.PERMISSION_ADMIN)
+	if err != nil {
+		return false, nil, err
+	}
+	blocked = append(blocked, blockedChildren...)
+
+	return len(blocked) == 0, blocked, nil
+}
+
+// CanExemptFromPolicy returns true if the user may exempt the dashboard from an org wide policy
+func (g *dashboardGuardianImpl) CanExemptFromPolicy(policyID string) (bool, error) {
+	return denyUnless(g.dashId, m.PERMISSION_ADMIN, g.CanAdmin)
+}
+
+// CanViewProvisioningSource returns true if the user may see where a provisioned dashboard is
+// loaded from. The source reveals file paths on the server, so this requires admin
+func (g *dashboardGuardianImpl) CanViewProvisioningSource() (bool, error) {
+	return g.CanAdmin()
+}
+
+// CanCollapseToInherited returns true if the user may remove the explicit permissions of the
+// dashboard so that only the ones of its folder apply. ErrWouldRemoveLastAdmin is returned when
+// the folder would not give the user admin, as they would lock themselves out
+func (g *dashboardGuardianImpl) CanCollapseToInherited() (bool, error) {
+	canAdmin, err := g.CanAdmin()
+	if err != nil || !canAdmin {
+		return false, err
+	}
+
+	dash, err := g.getDashboard()
+	if err != nil {
+		return false, err
+	}
+
+	folderGuardian := New(dash.FolderId, g.orgId, g.user)
+	canAdminFolder, err := folderGuardian.CanAdmin()
+	if err != nil {
+		return false, err
+	}
+	if !canAdminFolder {
+		return false, ErrWouldRemoveLastAdmin
+	}
+
+	return true, nil
+}
+
+// CanShareWithOrg returns true if the user may share the dashboard with another organization.
+// Besides admin on the dashboard this crosses org boundaries, so it is limited to server admins
+func (g *dashboardGuardianImpl) CanShareWithOrg(targetOrgID int64) (bool, error) {
+	if ok, err := denyUnless(g.dashId, m.PERMISSION_ADMIN, g.CanAdmin); err != nil || !ok {
+		return ok, err
+	}
+
+	if !g.user.IsGrafanaAdmin {
+		return false, PermissionDeniedError{DashboardId: g.dashId, Requirement: "server admin"}
+	}
+
+	return true, nil
+}
+
+// CanPromotePanelToWidget returns true if the user may turn a panel of the dashboard into a widget
+// for the whole organization. Org wide widgets are managed by org admins, so on top of admin on
+// the dashboard the user must be an org admin
+func (g *dashboardGuardianImpl) CanPromotePanelToWidget(panelID int) (bool, error) {
+	if ok, err := denyUnless(g.dashId, m.PERMISSION_ADMIN, g.CanAdmin); err != nil || !ok {
+		return ok, err
+	}
+
+	if g.user.OrgRole != m.ROLE_ADMIN {
+		return false, PermissionDeniedError{DashboardId: g.dashId, Requirement: "org admin"}
+	}
+
+	return true, nil
+}
+
+// CanEditRLS returns true if the user may change the row level security rules of the dashboard
+func (g *dashboardGuardianImpl) CanEditRLS() (bool, error) {
+	return denyUnless(g.dashId, m.PERMISSION_ADMIN, g.CanAdmin)
+}
+
+// CanRestoreFolder returns true if the user may restore the trashed folder together with all its
+// dashboards. Restoring needs the same permission as trashing, on the folder and on each
+// dashboard, the ids of the dashboards that cannot be restored are returned as blocked
+func (g *dashboardGuardianImpl) CanRestoreFolder() (bool, []int64, error) {
+	if canRestore, err := g.CanTrash(); err != nil || !canRestore {
+		return false, nil, err
+	}
+
+	blocked, err := g.getBlockedFolderChildren(g.dashId, m.PERMISSION_EDIT)
+	if err != nil {
+		return false, nil, err
+	}
+
+	return len(blocked) == 0, blocked, nil
+}
+
+// CanChangeUID returns true if the user may change the uid of the dashboard, which breaks links to
+// the old uid
+func (g *dashboardGuardianImpl) CanChangeUID() (bool, error) {
+	return denyUnless(g.dashId, m.PERMISSION_ADMIN, g.CanAdmin)
+}
+
+// CanEditRetentionPolicy returns true if the user may change how long versions and snapshots of the
+// dashboard are kept, which affects storage for the whole org
+func (g *dashboardGuardianImpl) CanEditRetentionPolicy() (bool, error) {
+	return denyUnless(g.dashId, m.PERMISSION_ADMIN, g.CanAdmin)
+}
+
+// CanBulkChangeOwner returns true if the user may hand all dashboards in the folder over to a new
+// owner. This needs admin on every dashboard and the new owner must be able to view them, the ids
+// of the dashboards failing either are returned as blocked. ErrOrgUserNotFound is returned if the
+// new owner is not a member of the org
+func (g *dashboardGuardianImpl) CanBulkChangeOwner(newOwnerID int64) (bool, []int64, error) {
+	children, err := getFolderChildren(g.orgId, g.dashId)
+	if err != nil {
+		return false, nil, err
+	}
+
+	ids := dashboardIds(children)
+	permissions, err := getPermissionsForUser(g.orgId, g.user, ids)
+	if err != nil {
+		return false, nil, err
+	}
+
+	ownerQuery := m.GetSignedInUserQuery{UserId: newOwnerID, OrgId: g.orgId}
+	if err := bus.Dispatch(&ownerQuery); err != nil {
+		return false, nil, err
+	}
+
+	if ownerQuery.Result.OrgId != g.orgId {
+		return false, nil, m.ErrOrgUserNotFound
+	}
+
+	ownerPermissions, err := getPermissionsForUser(g.orgId, ownerQuery.Result, ids)
+	if err != nil {
+		return false, nil, err
+	}
+
+	blocked := []int64{}
+	for _, child := range children {
+		if permissions[child.Id] < m.PERMISSION_ADMIN || ownerPermissions[child.Id] < m.PERMISSION_VIEW {
+			blocked = append(blocked, child.Id)
+		}
+	}
+
+	return len(blocked) == 0, blocked, nil
+}
+
+// CanSetAsOrgLanding returns true if the user may make the dashboard the home dashboard of the
+// organization, which requires being an org admin and viewing the dashboard
+func (g *dashboardGuardianImpl) CanSetAsOrgLanding() (bool, error) {
+	if g.user.OrgRole != m.ROLE_ADMIN {
+		return false, nil
+	}
+
+	return g.CanView()
+}
+
+// CanEditBranding returns true if the user may change the custom theme and branding of the
+// dashboard
+func (g *dashboardGuardianImpl) CanEditBranding() (bool, error) {
+	return denyUnless(g.dashId, m.PERMISSION_ADMIN, g.CanAdmin)
+}
+
+// CanBulkExportFolder returns true if the user may export all dashboards in the folder for backup.
+// A backup reveals the full dashboard models, so this needs edit on every dashboard, the ids of
+// the dashboards that cannot be exported are returned as blocked
+func (g *dashboardGuardianImpl) CanBulkExportFolder() (bool, []int64, error) {
+	if canView, err := g.CanView(); err != nil || !canView {
+		return false, nil, err
+	}
+
+	blocked, err := g.getBlockedFolderChildren(g.dashId, m.PERMISSION_EDIT)
+	if err != nil {
+		return false, nil, err
+	}
+
+	return len(blocked) == 0, blocked, nil
+}
+
+// CanConfigureWebhooks returns true if the user may set up webhooks for events of the dashboard
+func (g *dashboardGuardianImpl) CanConfigureWebhooks() (bool, error) {
+	return denyUnless(g.dashId, m.PERMISSION_ADMIN, g.CanAdmin)
+}
+
+// CanDetachFromProvisioning returns true if the user may detach the dashboard from provisioning,
+// which makes it editable in Grafana again and requires admin. ErrDashboardNotProvisioned is
+// returned for dashboards that are not provisioned, as there is nothing to detach
+func (g *dashboardGuardianImpl) CanDetachFromProvisioning() (bool, error) {
+	canAdmin, err := g.CanAdmin()
+	if err != nil || !canAdmin {
+		return false, err
+	}
+
+	query := m.GetProvisionedDashboardDataByIdQuery{DashboardId: g.dashId}
+	if err := bus.Dispatch(&query); err != nil {
+		return false, err
+	}
+
+	if query.Result == nil {
+		return false, m.ErrDashboardNotProvisioned
+	}
+
+	return true, nil
+}
+
+// CanGrantRole returns true if the user may add a permission for the given org role to the
+// dashboard. Besides admin on the dashboard the user's own role must include the granted role,
+// otherwise ErrGuardianGrantExceedsOwn is returned
+func (g *dashboardGuardianImpl) CanGrantRole(role m.RoleType) (bool, error) {
+	canAdmin, err := g.CanAdmin()
+	if err != nil || !canAdmin {
+		return false, err
+	}
+
+	if !g.user.OrgRole.Includes(role) {
+		return false, ErrGuardianGrantExceedsOwn
+	}
+
+	return true, nil
+}
+
+// CanMerge returns true if the user may merge the panels of the source dashboard into this one,
+// which needs edit on this dashboard and view on the source. A PermissionDeniedError tells which
+// side failed
+func (g *dashboardGuardianImpl) CanMerge(sourceDashboardID int64) (bool, error) {
+	if ok, err := denyUnless(g.dashId, m.PERMISSION_EDIT, g.CanEdit); err != nil || !ok {
+		return ok, err
+	}
+
+	sourceGuardian := New(sourceDashboardID, g.orgId, g.user)
+	return denyUnless(sourceDashboardID, m.PERMISSION_VIEW, sourceGuardian.CanView)
+}
+
+// CanConfigureSLO returns true if the user may attach SLO tracking to the dashboard
+func (g *dashboardGuardianImpl) CanConfigureSLO() (bool, error) {
+	return denyUnless(g.dashId, m.PERMISSION_EDIT, g.CanEdit)
+}
+
+// CanBulkStarFolder returns for each dashboard in the folder whether the user may star it, which
+// only requires view
+func (g *dashboardGuardianImpl) CanBulkStarFolder() (map[int64]bool, error) {
+	children, err := getFolderChildren(g.orgId, g.dashId)
+	if err != nil {
+		return nil, err
+	}
+
+	permissions, err := getPermissionsForUser(g.orgId, g.user, dashboardIds(children))
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[int64]bool, len(children))
+	for _, child := range children {
+		result[child.Id] = permissions[child.Id] >= m.PERMISSION_VIEW
+	}
+
+	return result, nil
+}
+
+// CanManageSilences returns true if the user may silence the alerts linked to the dashboard
+func (g *dashboardGuardianImpl) CanManageSilences() (bool, error) {
+	return denyUnless(g.dashId, m.PERMISSION_EDIT, g.CanEdit)
+}
+
+// CanCloneToOrg returns true if the user may copy the dashboard into a folder of another
+// organization. Crossing orgs is limited to server admins, who also need view on the dashboard
+// and, with their role in the target org, edit on the target folder. A PermissionDeniedError
+// tells which requirement failed
+func (g *dashboardGuardianImpl) CanCloneToOrg(targetOrgID int64, targetFolderID int64) (bool, error) {
+	if !g.user.IsGrafanaAdmin {
+		return false, PermissionDeniedError{DashboardId: g.dashId, Requirement: "server admin"}
+	}
+
+	if ok, err := denyUnless(g.dashId, m.PERMISSION_VIEW, g.CanView); err != nil || !ok {
+		return ok, err
+	}
+
+	query := m.GetSignedInUserQuery{UserId: g.user.UserId, OrgId: targetOrgID}
+	if err := bus.Dispatch(&query); err != nil {
+		return false, err
+	}
+
+	folderGuardian := New(targetFolderID, targetOrgID, query.Result)
+	return denyUnless(targetFolderID, m.PERMISSION_EDIT, folderGuardian.CanSave)
+}
+
+// CanEditDataMasking returns true if the user may change the rules that mask sensitive values on
+// the dashboard
+func (g *dashboardGuardianImpl) CanEditDataMasking() (bool, error) {
+	return denyUnless(g.dashId, m.PERMISSION_ADMIN, g.CanAdmin)
+}
+
+// CanPinGoldenVersion returns true if the user may mark the given version as the reference
+// version of the dashboard. This requires admin, a PermissionDeniedError is returned otherwise
+// and ErrDashboardVersionNotFound if the version does not exist
+func (g *dashboardGuardianImpl) CanPinGoldenVersion(version int) (bool, error) {
+	if ok, err := denyUnless(g.dashId, m.PERMISSION_ADMIN, g.CanAdmin); err != nil || !ok {
+		return ok, err
+	}
+
+	query := m.GetDashboardVersionQuery{DashboardId: g.dashId, OrgId: g.orgId, Version: version}
+	if err := bus.Dispatch(&query); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// CanTransferFolderAdmin returns true if the user may hand the administration of the folder over
+// to another user. This requires admin on the folder, a PermissionDeniedError is returned
+// otherwise and ErrOrgUserNotFound if the new admin is not a member of the org
+func (g *dashboardGuardianImpl) CanTransferFolderAdmin(newAdminID int64) (bool, error) {
+	if ok, err := denyUnless(g.dashId, m.PERMISSION_ADMIN, g.CanAdmin); err != nil || !ok {
+		return ok, err
+	}
+
+	query := m.GetSignedInUserQuery{UserId: newAdminID, OrgId: g.orgId}
+	if err := bus.Dispatch(&query); err != nil {
+		return false, err
+	}
+
+	if query.Result.OrgId != g.orgId {
+		return false, m.ErrOrgUserNotFound
+	}
+
+	return true, nil
+}
+
+// CanEditQueryTimeout returns true if the user may change the query timeout of the dashboard, which
+// affects the load on its data sources
+func (g *dashboardGuardianImpl) CanEditQueryTimeout() (bool, error) {
+	return denyUnless(g.dashId, m.PERMISSION_ADMIN, g.CanAdmin)
+}
+
+// CanEnableAnonymousAccess returns true if the user may open the dashboard to anonymous users
+func (g *dashboardGuardianImpl) CanEnableAnonymousAccess() (bool, error) {
+	return denyUnless(g.dashId, m.PERMISSION_ADMIN, g.CanAdmin)
+}
+
+// CanChangeInheritanceMode returns true if the user may switch how the dashboards in the folder
+// combine their own permissions with the folder's. This requires admin on the folder, and each
+// dashboard with its own permissions must keep the user admin without the folder's, otherwise
+// ErrWouldRemoveLastAdmin is returned
+func (g *dashboardGuardianImpl) CanChangeInheritanceMode() (bool, error) {
+	canAdmin, err := g.CanAdmin()
+	if err != nil || !canAdmin {
+		return false, err
+	}
+
+	if g.user.OrgRole == m.ROLE_ADMIN {
+		return true, nil
+	}
+
+	children, err := getFolderChildren(g.orgId, g.dashId)
+	if err != nil {
+		return false, err
+	}
+
+	for _, child := range children {
+		query := m.GetDashboardAclInfoListQuery{DashboardId: child.Id, OrgId: g.orgId}
+		if err := bus.Dispatch(&query); err != nil {
+			return false, err
+		}
+
+		own := []*m.DashboardAclInfoDTO{}
+		for _, p := range query.Result {
+			if !p.Inherited {
+				own = append(own, p)
+			}
+		}
+
+		if len(own) == 0 {
+			continue
+		}
+
+		keepsAdmin, err := g.checkAcl(m.PERMISSION_ADMIN, own)
+		if err != nil {
+			return false, err
+		}
+		if !keepsAdmin {
+			return false, ErrWouldRemoveLastAdmin
+		}
+	}
+
+	return true, nil
+}
+
+// CanSubscribeTeam returns true if the user may subscribe the given team to change notifications
+// for the dashboard, which requires viewing the dashboard and being an admin of the team
+func (g *dashboardGuardianImpl) CanSubscribeTeam(teamID int64) (bool, error) {
+	if ok, err := denyUnless(g.dashId, m.PERMISSION_VIEW, g.CanView); err != nil || !ok {
+		return ok, err
+	}
+
+	return g.denyUnlessTeamAdmin(teamID)
+}
+
+// CanConfigureAuditLogging returns true if the user may toggle detailed audit logging for the
+// dashboard
+func (g *dashboardGuardianImpl) CanConfigureAuditLogging() (bool, error) {
+	return denyUnless(g.dashId, m.PERMISSION_ADMIN, g.CanAdmin)
+}
+
+// CanImportAcl returns true if the user may restore the permissions of the dashboard from an acl
+// exported with ExportAcl. This requires admin, and every imported grant has to be one the user
+// could give themselves, with a known permission and a role no higher than their own. Otherwise
+// a GrantExceedsOwnError names the first one that is not
+func (g *dashboardGuardianImpl) CanImportAcl(exported []byte) (bool, error) {
+	canAdmin, err := g.CanAdmin()
+	if err != nil || !canAdmin {
+		return false, err
+	}
+
+	var grants []*m.DashboardAclInfoDTO
+	if err := json.Unmarshal(exported, &grants); err != nil {
+		return false, err
+	}
+
+	for _, grant := range grants {
+		// inherited entries belong to the folder and are not applied to the dashboard
+		if grant.Inherited {
+			continue
+		}
+
+		// admin covers every known permission, so only unknown levels exceed it
+		if !isKnownPermission(grant.Permission) || (grant.Role != nil && !g.user.OrgRole.Includes(*grant.Role)) {
+			exceeded := GrantExceedsOwnError{UserId: grant.UserId, TeamId: grant.TeamId, Permission: grant.Permission}
+			if grant.Role != nil {
+				exceeded.Role = *grant.Role
+			}
+			return false, exceeded
+		}
+	}
+
+	return true, nil
+}
+
+// CanAddToNavSection returns true if the user may add the dashboard to a curated navigation
+// section. Sections are shared by the whole organization, so besides viewing the dashboard this
+// is limited to org admins. A PermissionDeniedError tells which side failed
+func (g *dashboardGuardianImpl) CanAddToNavSection(sectionID string) (bool, error) {
+	if ok, err := denyUnless(g.dashId, m.PERMISSION_VIEW, g.CanView); err != nil || !ok {
+		return ok, err
+	}
+
+	if g.user.OrgRole != m.ROLE_ADMIN {
+		return false, PermissionDeniedError{DashboardId: g.dashId, Requirement: "org admin"}
+	}
+
+	return true, nil
+}
+
+// CanSaveInFolder returns true if the user may save a dashboard into the given folder, e.g. when
+// saving a copy of this dashboard somewhere else. The General folder (0) uses the default
+// permissions of the org roles, just like CanSave does for a new dashboard
+func (g *dashboardGuardianImpl) CanSaveInFolder(folderID int64) (bool, error) {
+	folderGuardian := New(folderID, g.orgId, g.user)
+	return folderGuardian.CanSave()
+}
+
+// CanDeprecate returns true if the user may mark the dashboard as deprecated
+func (g *dashboardGuardianImpl) CanDeprecate() (bool, error) {
+	return denyUnless(g.dashId, m.PERMISSION_EDIT, g.CanEdit)
+}
+
+// CanViewOrError is like CanView, but returns a PermissionDeniedError when the user is denied
+func (g *dashboardGuardianImpl) CanViewOrError() error {
+	return g.orError(m.PERMISSION_VIEW, g.CanView)
+}
+
+// CanEditOrError is like CanEdit, but returns a PermissionDeniedError when the user is denied
+func (g *dashboardGuardianImpl) CanEditOrError() error {
+	return g.orError(m.PERMISSION_EDIT, g.CanEdit)
+}
+
+// CanSaveOrError is like CanSave, but returns a PermissionDeniedError when the user is denied
+func (g *dashboardGuardianImpl) CanSaveOrError() error {
+	return g.orError(m.PERMISSION_EDIT, g.CanSave)
+}
+
+// CanAdminOrError is like CanAdmin, but returns a PermissionDeniedError when the user is denied
+func (g *dashboardGuardianImpl) CanAdminOrError() error {
+	return g.orError(m.PERMISSION_ADMIN, g.CanAdmin)
+}
+
+// CanEditDefaultVariables returns true if the user may set the default values of the dashboard
+// variables
+func (g *dashboardGuardianImpl) CanEditDefaultVariables() (bool, error) {
+	return denyUnless(g.dashId, m.PERMISSION_EDIT, g.CanEdit)
+}
+
+// FilterDashboardListResults returns the dashboards listed by a dashboard list panel that the user
+// may view, in their original order. All dashboards and the permissions inherited from their
+// folders are checked in a single query
+func (g *dashboardGuardianImpl) FilterDashboardListResults(dashboardIDs []int64) ([]int64, error) {
+	canView, err := BatchCanView(g.orgId, dashboardIDs, g.user)
+	if err != nil {
+		return nil, err
+	}
+
+	viewable := []int64{}
+	for _, id := range dashboardIDs {
+		if canView[id] {
+			viewable = append(viewable, id)
+		}
+	}
+
+	return viewable, nil
+}
+
+// EvaluateForUser returns true if another user has the permission on the dashboard, without
+// impersonating them. The acl already loaded by this guardian is reused. The user has to be in
+// the organization of the dashboard, otherwise ErrGuardianOrgMismatch is returned
+func (g *dashboardGuardianImpl) EvaluateForUser(user *m.SignedInUser, permission m.PermissionType) (bool, error) {
+	if user.OrgId != g.orgId {
+		return false, ErrGuardianOrgMismatch
+	}
+
+	acl, err := g.GetAcl()
+	if err != nil {
+		return false, err
+	}
+
+	other := &dashboardGuardianImpl{
+		user:        user,
+		dashId:      g.dashId,
+		orgId:       g.orgId,
+		dashboard:   g.dashboard,
+		acl:         acl,
+		permissions: map[m.PermissionType]bool{},
+		log:         g.log,
+	}
+
+	return other.HasPermission(permission)
+}
+
+// CanEditContactPointOverrides returns true if the user may override the alert contact points of
+// the dashboard, which can send alerts outside the org
+func (g *dashboardGuardianImpl) CanEditContactPointOverrides() (bool, error) {
+	return denyUnless(g.dashId, m.PERMISSION_ADMIN, g.CanAdmin)
+}
+
+// CanDeleteFolderContents returns true if the user may delete every dashboard in the folder, which
+// needs the same permission as deleting each of them on its own. An empty folder has nothing
+// protected in it, so true is returned
+func (g *dashboardGuardianImpl) CanDeleteFolderContents() (bool, error) {
+	blocked, err := g.getBlockedFolderChildren(g.dashId, m.PERMISSION_EDIT)
+	if err != nil {
+		return false, err
+	}
+
+	return len(blocked) == 0, nil
+}
+
+// CanPromoteToSharedSpace returns true if the user may promote the folder to a space shared with
+// the whole organization. Besides admin on the folder this changes the structure of the org, so it
+// is limited to org admins. A PermissionDeniedError tells which requirement failed
+func (g *dashboardGuardianImpl) CanPromoteToSharedSpace() (bool, error) {
+	if ok, err := denyUnless(g.dashId, m.PERMISSION_ADMIN, g.CanAdmin); err != nil || !ok {
+		return ok, err
+	}
+
+	if g.user.OrgRole != m.ROLE_ADMIN {
+		return false, PermissionDeniedError{DashboardId: g.dashId, Requirement: "org admin"}
+	}
+
+	return true, nil
+}
+
+// CanEditFeatureFlags returns true if the user may toggle feature flags, like experimental panels,
+// for the dashboard
+func (g *dashboardGuardianImpl) CanEditFeatureFlags() (bool, error) {
+	return denyUnless(g.dashId, m.PERMISSION_ADMIN, g.CanAdmin)
+}
+
+// ExplainAccess evaluates the permission like HasPermission does, but also reports the acl that
+// was evaluated and the item that gave the user the permission. It changes nothing and is meant
+// for troubleshooting why a user can or cannot access the dashboard
+func (g *dashboardGuardianImpl) ExplainAccess(permission m.PermissionType) (*AccessExplanation, error) {
+	explanation := &AccessExplanation{DashboardId: g.dashId, Permission: permission}
+
+	acl, err := g.GetAcl()
+	if err != nil {
+		return nil, err
+	}
+	explanation.Acl = acl
+
+	if g.user.OrgRole == m.ROLE_ADMIN {
+		explanation.Allowed = true
+		explanation.OrgAdmin = true
+		return explanation, nil
+	}
+
+	match, err := g.matchAcl(permission, acl)
+	if err != nil {
+		return nil, err
+	}
+
+	explanation.Allowed = match != nil
+	explanation.Match = match
+	return explanation, nil
+}
+
+// CanEditPIIRedaction returns true if the user may change what is redacted when the dashboard is
+// rendered or exported
+func (g *dashboardGuardianImpl) CanEditPIIRedaction() (bool, error) {
+	return denyUnless(g.dashId, m.PERMISSION_ADMIN, g.CanAdmin)
+}
+
+// CanMoveOutOfLockedFolder returns true if the user may move the dashboard to the new folder. This
+// needs edit on the dashboard and on the new folder like any move, and when the current folder is
+// locked, which is set by "locked" in its json model, admin on the current folder as well
+func (g *dashboardGuardianImpl) CanMoveOutOfLockedFolder(newFolderID int64) (bool, error) {
+	canSave, err := g.CanSave()
+	if err != nil || !canSave {
+		return false, err
+	}
+
+	canSaveInFolder, err := g.CanSaveInFolder(newFolderID)
+	if err != nil || !canSaveInFolder {
+		return false, err
+	}
+
+	dash, err := g.getDashboard()
+	if err != nil {
+		return false, err
+	}
+
+	if dash.FolderId == 0 || dash.FolderId == newFolderID {
+		return true, nil
+	}
+
+	query := m.GetDashboardQuery{Id: dash.FolderId, OrgId: g.orgId}
+	if err := bus.Dispatch(&query); err != nil {
+		return false, err
+	}
+
+	if !query.Result.Data.Get("locked").MustBool() {
+		return true, nil
+	}
+
+	return New(dash.FolderId, g.orgId, g.user).CanAdmin()
+}
+
+// CanEditExportWatermark returns true if the user may configure the watermark added to exports of
+// the dashboard
+func (g *dashboardGuardianImpl) CanEditExportWatermark() (bool, error) {
+	return denyUnless(g.dashId, m.PERMISSION_ADMIN, g.CanAdmin)
+}
+
+// CanEditEmbedAllowlist returns true if the user may change which domains can embed the dashboard
+// in an iframe
+func (g *dashboardGuardianImpl) CanEditEmbedAllowlist() (bool, error) {
+	return denyUnless(g.dashId, m.PERMISSION_ADMIN, g.CanAdmin)
+}
+
+// CanOverrideInheritanceTemporarily returns true if the user may temporarily detach the dashboard
+// from the permissions of its folder. Every check is logged as warning
+func (g *dashboardGuardianImpl) CanOverrideInheritanceTemporarily() (bool, error) {
+	ok, err := denyUnless(g.dashId, m.PERMISSION_ADMIN, g.CanAdmin)
+	g.log.Warn("Temporary override of inherited permissions checked", "userId", g.user.UserId, "orgId", g.orgId, "uname", g.user.Login, "dashId", g.dashId, "allowed", ok)
+	return ok, err
+}
+
+// CanStar returns true if the user may star the dashboard. Starring only needs view, this is the
+// same as CanView but makes the intent clear where it is called
+func (g *dashboardGuardianImpl) CanStar() (bool, error) {
+	return g.CanView()
+}
+
+// CanEditDatasourceFailover returns true if the user may configure which data sources the dashboard
+// fails over to
+func (g *dashboardGuardianImpl) CanEditDatasourceFailover() (bool, error) {
+	return denyUnless(g.dashId, m.PERMISSION_ADMIN, g.CanAdmin)
+}
+
+func (g *dashboardGuardianImpl) HasPermission(permission m.PermissionType) (bool, error) {
+	if g.user.OrgRole == m.ROLE_ADMIN {
+		return g.logHasPermissionResult(permission, true, nil)
+	}
+
+	// the acl and teams of the user don't change during a request, so each permission is only
+	// checked once per guardian
+	if result, ok := g.permissions[permission]; ok {
+		return g.logHasPermissionResult(permission, result, nil)
+	}
+
+	acl, err := g.GetAcl()
+	if err != nil {
+		return g.logHasPermissionResult(permission, false, err)
+	}
+
+	result, err := g.checkAcl(permission, acl)
+	if err == nil {
+		g.permissions[permission] = result
+	}
+
+	return g.logHasPermissionResult(permission, result, err)
+}
+
+func (g *dashboardGuardianImpl) logHasPermissionResult(permission m.PermissionType, hasPermission bool, err error) (bool, error) {
+	if err != nil {
+		metrics.MGuardianErrors.Inc()
+		return hasPermission, err
+	}
+
+	if hasPermission {
+		metrics.MGuardianDecisions.WithLabelValues(permission.String(), "granted").Inc()
+		g.log.Debug("User granted access to execute action", "userId", g.user.UserId, "orgId", g.orgId, "uname", g.user.Login, "dashId", g.dashId, "action", permission)
+	} else {
+		metrics.MGuardianDecisions.WithLabelValues(permission.String(), "denied").Inc()
+		g.log.Debug("User denied access to execute action", "userId", g.user.UserId, "orgId", g.orgId, "uname", g.user.Login, "dashId", g.dashId, "action", permission)
+	}
+
+	return hasPermission, err
+}
+
+func (g *dashboardGuardianImpl) checkAcl(permission m.PermissionType, acl []*m.DashboardAclInfoDTO) (bool, error) {
+	match, err := g.matchAcl(permission, acl)
+	return match != nil, err
+}
+
+// matchAcl returns the first acl item that gives the user the permission, or nil if none does
+func (g *dashboardGuardianImpl) matchAcl(permission m.PermissionType, acl []*m.DashboardAclInfoDTO) (*m.DashboardAclInfoDTO, error) {
+	orgRole := g.user.OrgRole
+	teamAclItems := []*m.DashboardAclInfoDTO{}
+
+	for _, p := range acl {
+		// user match
+		if !g.user.IsAnonymous && p.UserId > 0 {
+			if p.UserId == g.user.UserId && p.Permission >= permission {
+				return p, nil
+			}
+		}
+
+		// role match
+		if p.Role != nil {
+			if *p.Role == orgRole && p.Permission >= permission {
+				return p, nil
+			}
+		}
+
+		// remember this rule for later
+		if p.TeamId > 0 {
+			teamAclItems = append(teamAclItems, p)
+		}
+	}
+
+	// do we have team rules?
+	if len(teamAclItems) == 0 {
+		return nil, nil
+	}
+
+	// load teams
+	teams, err := g.getTeams()
+	if err != nil {
+		return nil, err
+	}
+
+	// evaluate team rules
+	for _, p := range acl {
+		for _, ug := range teams {
+			if ug.Id == p.TeamId && p.Permission >= permission {
+				return p, nil
+			}
+		}
+	}
+
+	return nil, nil
+}
+
+func (g *dashboardGuardianImpl) CheckPermissionBeforeUpdate(permission m.PermissionType, updatePermissions []*m.DashboardAcl) (bool, error) {
+	acl := []*m.DashboardAclInfoDTO{}
+	adminRole := m.ROLE_ADMIN
+	everyoneWithAdminRole := &m.DashboardAclInfoDTO{DashboardId: g.dashId, UserId: 0, TeamId: 0, Role: &adminRole, Permission: m.PERMISSION_ADMIN}
+
+	// validate that duplicate permissions don't exists
+	for _, p := range updatePermissions {
+		aclItem := &m.DashboardAclInfoDTO{DashboardId: p.DashboardId, UserId: p.UserId, TeamId: p.TeamId, Role: p.Role, Permission: p.Permission}
+		if aclItem.IsDuplicateOf(everyoneWithAdminRole) {
+			return false, ErrGuardianPermissionExists
+		}
+
+		for _, a := range acl {
+			if a.IsDuplicateOf(aclItem) {
+				return false, ErrGuardianPermissionExists
+			}
+		}
+
+		acl = append(acl, aclItem)
+	}
+
+	existingPermissions, err := g.GetAcl()
+	if err != nil {
+		return false, err
+	}
+
+	// validate overridden permissions to be higher
+	for _, a := range acl {
+		for _, existingPerm := range existingPermissions {
+			if !existingPerm.Inherited {
+				continue
+			}
+
+			if a.IsDuplicateOf(existingPerm) && a.Permission <= existingPerm.Permission {
+				return false, ErrGuardianOverride
+			}
+		}
+	}
+
+	if g.user.OrgRole == m.ROLE_ADMIN {
+		return true, nil
+	}
+
+	return g.checkAcl(permission, existingPermissions)
+}
+
+// GetAcl returns dashboard acl
+func (g *dashboardGuardianImpl) GetAcl() ([]*m.DashboardAclInfoDTO, error) {
+	if g.acl != nil {
+		return g.acl, nil
+	}
+
+	query := m.GetDashboardAclInfoListQuery{DashboardId: g.dashId, OrgId: g.orgId}
+	if err := bus.Dispatch(&query); err != nil {
+		return nil, err
+	}
+
+	g.acl = query.Result
+	return g.acl, nil
+}
+
+// ExportAcl returns the dashboard acl for compliance reporting if the user is allowed to export it
+func (g *dashboardGuardianImpl) ExportAcl() ([]*m.DashboardAclInfoDTO, error) {
+	canExport, err := g.CanExportAcl()
+	if err != nil {
+		return nil, err
+	}
+
+	if !canExport {
+		return nil, PermissionDeniedError{DashboardId: g.dashId, Requirement: m.PERMISSION_ADMIN.String()}
+	}
+
+	return g.GetAcl()
+}
+
+func getFolderChildren(orgId int64, folderId int64) ([]*m.Dashboard, error) {
+	query := m.GetDashboardsByFolderIdQuery{OrgId: orgId, FolderId: folderId}
+	if err := bus.Dispatch(&query); err != nil {
+		return nil, err
+	}
+
+	return query.Result, nil
+}
+
+// getPermissionsForUser returns the highest permission the user has on each of the dashboards.
+// The acls are loaded in a single query and matched the same way as by HasPermission, so an acl
+// item inherited from the folder only counts when it is for the user, one of their teams or their
+// role. Dashboards the user has no access to are missing from the map
+func getPermissionsForUser(orgId int64, user *m.SignedInUser, dashIds []int64) (map[int64]m.PermissionType, error) {
+	permissions := map[int64]m.PermissionType{}
+	if len(dashIds) == 0 {
+		return permissions, nil
+	}
+
+	if user.OrgRole == m.ROLE_ADMIN {
+		for _, id := range dashIds {
+			permissions[id] = m.PERMISSION_ADMIN
+		}
+		return permissions, nil
+	}
+
+	acls, err := BatchGetAcl(orgId, dashIds)
+	if err != nil {
+		return nil, err
+	}
+
+	// a single guardian matches all acls, so the teams of the user are loaded at most once
+	g := &dashboardGuardianImpl{user: user, orgId: orgId, log: log.New("dashboard.permissions")}
+	for id, acl := range acls {
+		for _, permission := range []m.PermissionType{m.PERMISSION_ADMIN, m.PERMISSION_EDIT, m.PERMISSION_VIEW} {
+			match, err := g.matchAcl(permission, acl)
+			if err != nil {
+				return nil, err
+			}
+			if match != nil {
+				permissions[id] = permission
+				break
+			}
+		}
+	}
+
+	return permissions, nil
+}
+
+// getBlockedFolderChildren returns the ids of the dashboards in the folder on which the user has
+// less than the given permission
+func (g *dashboardGuardianImpl) getBlockedFolderChildren(folderId int64, permission m.PermissionType) ([]int64, error) {
+	children, err := getFolderChildren(g.orgId, folderId)
+	if err != nil {
+		return nil, err
+	}
+
+	permissions, err := getPermissionsForUser(g.orgId, g.user, dashboardIds(children))
+	if err != nil {
+		return nil, err
+	}
+
+	blocked := []int64{}
+	for _, child := range children {
+		if permissions[child.Id] < permission {
+			blocked = append(blocked, child.Id)
+		}
+	}
+
+	return blocked, nil
+}
+
+func dashboardIds(dashboards []*m.Dashboard) []int64 {
+	ids := make([]int64, 0, len(dashboards))
+	for _, d := range dashboards {
+		ids = append(ids, d.Id)
+	}
+
+	return ids
+}
+
+// getDashboardDatasources returns the names of the data sources used by the panels and queries of
+// the dashboard, including panels in rows. Panels using the default data source don't name it
+func getDashboardDatasources(dash *m.Dashboard) []string {
+	names := []string{}
+	seen := map[string]bool{}
+	add := func(name string) {
+		if name != "" && !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+
+	var addPanels func(panels []interface{})
+	addPanels = func(panels []interface{}) {
+		for _, panelObj := range panels {
+			panel := simplejson.NewFromAny(panelObj)
+			add(panel.Get("datasource").MustString())
+			for _, target := range panel.Get("targets").MustArray() {
+				add(simplejson.NewFromAny(target).Get("datasource").MustString())
+			}
+			// collapsed rows keep their panels nested
+			addPanels(panel.Get("panels").MustArray())
+		}
+	}
+
+	if dash.Data == nil {
+		return names
+	}
+
+	addPanels(dash.Data.Get("panels").MustArray())
+	for _, row := range dash.Data.Get("rows").MustArray() {
+		addPanels(simplejson.NewFromAny(row).Get("panels").MustArray())
+	}
+
+	return names
+}
+
+// getBlockedDatasource returns the name of the first data source the user cannot query, or an
+// empty string if all can be queried. Without a data source permission handler on the bus every
+// org member may query every data source
+func (g *dashboardGuardianImpl) getBlockedDatasource(dsNames []string) (string, error) {
+	datasources := []*m.DataSource{}
+	for _, name := range dsNames {
+		query := m.GetDataSourceByNameQuery{Name: name, OrgId: g.orgId}
+		if err := bus.Dispatch(&query); err != nil {
+			return "", err
+		}
+		datasources = append(datasources, query.Result)
+	}
+
+	filterQuery := m.DatasourcesPermissionFilterQuery{User: g.user, Datasources: datasources}
+	if err := bus.Dispatch(&filterQuery); err != nil {
+		if err != bus.ErrHandlerNotFound {
+			return "", err
+		}
+
+		return "", nil
+	}
+
+	allowed := map[string]bool{}
+	for _, ds := range filterQuery.Result {
+		allowed[ds.Name] = true
+	}
+
+	for _, name := range dsNames {
+		if !allowed[name] {
+			return name, nil
+		}
+	}
+
+	return "", nil
+}
+
+// isKnownPermission returns true for the permissions an acl item can grant
+func isKnownPermission(permission m.PermissionType) bool {
+	switch permission {
+	case m.PERMISSION_VIEW, m.PERMISSION_EDIT, m.PERMISSION_ADMIN:
+		return true
+	}
+
+	return false
+}
+
+// denyUnless turns a failed check into a PermissionDeniedError for the given dashboard
+func denyUnless(dashId int64, permission m.PermissionType, check func() (bool, error)) (bool, error) {
+	ok, err := check()
+	if err != nil || ok {
+		return ok, err
+	}
+
+	return false, PermissionDeniedError{DashboardId: dashId, Requirement: permission.String()}
+}
+
+// denyUnlessTeamAdmin returns a PermissionDeniedError unless the user is an admin of the team
+func (g *dashboardGuardianImpl) denyUnlessTeamAdmin(teamID int64) (bool, error) {
+	err := teamguardian.CanAdmin(bus.GetBus(), g.orgId, teamID, g.user)
+	if err == m.ErrNotAllowedToUpdateTeam || err == m.ErrNotAllowedToUpdateTeamInDifferentOrg {
+		return false, PermissionDeniedError{DashboardId: g.dashId, Requirement: "team admin"}
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// orError runs the check and turns a denial into a PermissionDeniedError. The dashboard is only
+// loaded on denial, to tell the caller whether it is a folder
+func (g *dashboardGuardianImpl) orError(permission m.PermissionType, check func() (bool, error)) error {
+	ok, err := check()
+	if err != nil || ok {
+		return err
+	}
+
+	// the General folder is not stored as a dashboard
+	isFolder := g.dashId == 0
+	if !isFolder {
+		dash, err := g.getDashboard()
+		if err != nil {
+			return err
+		}
+		isFolder = dash.IsFolder
+	}
+
+	return PermissionDeniedError{DashboardId: g.dashId, IsFolder: isFolder, Requirement: permission.String()}
+}
+
+func (g *dashboardGuardianImpl) getDashboard() (*m.Dashboard, error) {
+	if g.dashboard != nil {
+		return g.dashboard, nil
+	}
+
+	query := m.GetDashboardQuery{Id: g.dashId, OrgId: g.orgId}
+	if err := bus.Dispatch(&query); err != nil {
+		return nil, err
+	}
+
+	g.dashboard = query.Result
+	return g.dashboard, nil
+}
+
+func (g *dashboardGuardianImpl) getTeams() ([]*m.TeamDTO, error) {
+	if g.teams != nil {
+		return g.teams, nil
+	}
+
+	query := m.GetTeamsByUserQuery{OrgId: g.orgId, UserId: g.user.UserId}
+	err := bus.Dispatch(&query)
+
+	g.teams = query.Result
+	return query.Result, err
+}
+
+type FakeDashboardGuardian struct {
+	DashId                                 int64
+	OrgId                                  int64
+	User                                   *m.SignedInUser
+	CanSaveValue                           bool
+	CanEditValue                           bool
+	CanViewValue                           bool
+	CanAdminValue                          bool
+	HasPermissionValue                     bool
+	CheckPermissionBeforeUpdateValue       bool
+	CheckPermissionBeforeUpdateError       error
+	GetAclValue                            []*m.DashboardAclInfoDTO
+	CanSetSharedTimeRangeValue             bool
+	CanDeleteWithDependenciesValue         bool
+	CanDeleteWithDependenciesBlockers      []string
+	CanCreateWithDefaultTemplateValue      bool
+	CanExportAclValue                      bool
+	ExportAclValue                         []*m.DashboardAclInfoDTO
+	CanEmbedIntoValue                      bool
+	CanRestoreVersionValue                 bool
+	CanAckAlertsValue                      bool
+	CanTrashValue                          bool
+	CanPurgeValue                          bool
+	CanDuplicateFolderValue                bool
+	CanManageDatasourcePropagationValue    bool
+	CanBulkTagFolderValue                  bool
+	GetEditableFolderDashboardsValue       []int64
+	CanShareToChannelValue                 bool
+	CanCreateFromTemplateValue             bool
+	IsSoleAdminValue                       bool
+	CanPauseAlertsValue                    bool
+	CanAttachToIncidentValue               bool
+	CanSetAsTeamDefaultValue               bool
+	CanConfigureAccessRequestsValue        bool
+	CanRequestAccessValue                  bool
+	CanApproveAccessRequestValue           bool
+	CanPinToPersonalHomeValue              bool
+	CanEditLinksValue                      bool
+	CanExportCSVValue                      bool
+	CanEditCachingPolicyValue              bool
+	CanBulkMoveFolderContentsValue         bool
+	CanBulkMoveFolderContentsBlocked       []int64
+	CanAssignToWorkspaceValue              bool
+	CanSeeLockHolderValue                  bool
+	CanCleanupSnapshotsValue               bool
+	CanApplyTemplateRecursivelyValue       bool
+	CanApplyTemplateRecursivelyBlocked     []int64
+	CanExemptFromPolicyValue               bool
+	CanViewProvisioningSourceValue         bool
+	CanCollapseToInheritedValue            bool
+	CanShareWithOrgValue                   bool
+	CanPromotePanelToWidgetValue           bool
+	CanEditRLSValue                        bool
+	CanRestoreFolderValue                  bool
+	CanRestoreFolderBlocked                []int64
+	CanChangeUIDValue                      bool
+	CanEditRetentionPolicyValue            bool
+	CanBulkChangeOwnerValue                bool
+	CanBulkChangeOwnerBlocked              []int64
+	CanSetAsOrgLandingValue                bool
+	CanEditBrandingValue                   bool
+	CanBulkExportFolderValue               bool
+	CanBulkExportFolderBlocked             []int64
+	CanConfigureWebhooksValue              bool
+	CanDetachFromProvisioningValue         bool
+	CanGrantRoleValue                      bool
+	CanMergeValue                          bool
+	CanConfigureSLOValue                   bool
+	CanBulkStarFolderValue                 map[int64]bool
+	CanManageSilencesValue                 bool
+	CanCloneToOrgValue                     bool
+	CanEditDataMaskingValue                bool
+	CanPinGoldenVersionValue               bool
+	CanTransferFolderAdminValue            bool
+	CanEditQueryTimeoutValue               bool
+	CanEnableAnonymousAccessValue          bool
+	CanChangeInheritanceModeValue          bool
+	CanSubscribeTeamValue                  bool
+	CanConfigureAuditLoggingValue          bool
+	CanImportAclValue                      bool
+	CanAddToNavSectionValue                bool
+	CanSaveInFolderValue                   bool
+	CanDeprecateValue                      bool
+	CanEditDefaultVariablesValue           bool
+	FilterDashboardListResultsValue        []int64
+	EvaluateForUserValue                   bool
+	CanEditContactPointOverridesValue      bool
+	CanDeleteFolderContentsValue           bool
+	CanPromoteToSharedSpaceValue           bool
+	CanEditFeatureFlagsValue               bool
+	ExplainAccessValue                     *AccessExplanation
+	CanEditPIIRedactionValue               bool
+	CanMoveOutOfLockedFolderValue          bool
+	CanEditExportWatermarkValue            bool
+	CanEditEmbedAllowlistValue             bool
+	CanOverrideInheritanceTemporarilyValue bool
+	CanStarValue                           bool
+	CanEditDatasourceFailoverValue         bool
+}
+
+func (g *FakeDashboardGuardian) CanSave() (bool, error) {
+	return g.CanSaveValue, nil
+}
+
+func (g *FakeDashboardGuardian) CanEdit() (bool, error) {
+	return g.CanEditValue, nil
+}
+
+func (g *FakeDashboardGuardian) CanView() (bool, error) {
+	return g.CanViewValue, nil
+}
+
+func (g *FakeDashboardGuardian) CanAdmin() (bool, error) {
+	return g.CanAdminValue, nil
+}
+
+func (g *FakeDashboardGuardian) HasPermission(permission m.PermissionType) (bool, error) {
+	return g.HasPermissionValue, nil
+}
+
+func (g *FakeDashboardGuardian) CheckPermissionBeforeUpdate(permission m.PermissionType, updatePermissions []*m.DashboardAcl) (bool, error) {
+	return g.CheckPermissionBeforeUpdateValue, g.CheckPermissionBeforeUpdateError
+}
+
+func (g *FakeDashboardGuardian) GetAcl() ([]*m.DashboardAclInfoDTO, error) {
+	return g.GetAclValue, nil
+}
+
+func (g *FakeDashboardGuardian) CanSetSharedTimeRange() (bool, error) {
+	return g.CanSetSharedTimeRangeValue, nil
+}
+
+func (g *FakeDashboardGuardian) CanDeleteWithDependencies() (bool, []string, error) {
+	return g.CanDeleteWithDependenciesValue, g.CanDeleteWithDependenciesBlockers, nil
+}
+
+func (g *FakeDashboardGuardian) CanCreateWithDefaultTemplate(folderID int64) (bool, error) {
+	return g.CanCreateWithDefaultTemplateValue, nil
+}
+
+func (g *FakeDashboardGuardian) CanExportAcl() (bool, error) {
+	return g.CanExportAclValue, nil
+}
+
+func (g *FakeDashboardGuardian) ExportAcl() ([]*m.DashboardAclInfoDTO, error) {
+	return g.ExportAclValue, nil
+}
+
+func (g *FakeDashboardGuardian) CanEmbedInto(parentDashboardID int64) (bool, error) {
+	return g.CanEmbedIntoValue, nil
+}
+
+func (g *FakeDashboardGuardian) CanRestoreVersion(version int) (bool, error) {
+	return g.CanRestoreVersionValue, nil
+}
+
+func (g *FakeDashboardGuardian) CanAckAlerts() (bool, error) {
+	return g.CanAckAlertsValue, nil
+}
+
+func (g *FakeDashboardGuardian) CanTrash() (bool, error) {
+	return g.CanTrashValue, nil
+}
+
+func (g *FakeDashboardGuardian) CanPurge() (bool, error) {
+	return g.CanPurgeValue, nil
+}
+
+func (g *FakeDashboardGuardian) CanDuplicateFolder(targetParentID int64) (bool, error) {
+	return g.CanDuplicateFolderValue, nil
+}
+
+func (g *FakeDashboardGuardian) CanManageDatasourcePropagation() (bool, error) {
+	return g.CanManageDatasourcePropagationValue, nil
+}
+
+func (g *FakeDashboardGuardian) CanBulkTagFolder() (bool, error) {
+	return g.CanBulkTagFolderValue, nil
+}
+
+func (g *FakeDashboardGuardian) GetEditableFolderDashboards() ([]int64, error) {
+	return g.GetEditableFolderDashboardsValue, nil
+}
+
+func (g *FakeDashboardGuardian) CanShareToChannel(integration string) (bool, error) {
+	return g.CanShareToChannelValue, nil
+}
+
+func (g *FakeDashboardGuardian) CanCreateFromTemplate(targetFolderID int64, dsNames []string) (bool, error) {
+	return g.CanCreateFromTemplateValue, nil
+}
+
+func (g *FakeDashboardGuardian) IsSoleAdmin() (bool, error) {
+	return g.IsSoleAdminValue, nil
+}
+
+func (g *FakeDashboardGuardian) CanPauseAlerts() (bool, error) {
+	return g.CanPauseAlertsValue, nil
+}
+
+func (g *FakeDashboardGuardian) CanAttachToIncident(incidentID string) (bool, error) {
+	return g.CanAttachToIncidentValue, nil
+}
+
+func (g *FakeDashboardGuardian) CanSetAsTeamDefault(teamID int64) (bool, error) {
+	return g.CanSetAsTeamDefaultValue, nil
+}
+
+func (g *FakeDashboardGuardian) CanConfigureAccessRequests() (bool, error) {
+	return g.CanConfigureAccessRequestsValue, nil
+}
+
+func (g *FakeDashboardGuardian) CanRequestAccess(level m.PermissionType) (bool, error) {
+	return g.CanRequestAccessValue, nil
+}
+
+func (g *FakeDashboardGuardian) CanApproveAccessRequest(targetUserID int64, level m.PermissionType) (bool, error) {
+	return g.CanApproveAccessRequestValue, nil
+}
+
+func (g *FakeDashboardGuardian) CanPinToPersonalHome() (bool, error) {
+	return g.CanPinToPersonalHomeValue, nil
+}
+
+func (g *FakeDashboardGuardian) CanEditLinks() (bool, error) {
+	return g.CanEditLinksValue, nil
+}
+
+func (g *FakeDashboardGuardian) CanExportCSV(dsNames []string) (bool, error) {
+	return g.CanExportCSVValue, nil
+}
+
+func (g *FakeDashboardGuardian) CanEditCachingPolicy() (bool, error) {
+	return g.CanEditCachingPolicyValue, nil
+}
+
+func (g *FakeDashboardGuardian) CanBulkMoveFolderContents(targetFolderID int64) (bool, []int64, error) {
+	return g.CanBulkMoveFolderContentsValue, g.CanBulkMoveFolderContentsBlocked, nil
+}
+
+func (g *FakeDashboardGuardian) CanAssignToWorkspace(workspaceID int64) (bool, error) {
+	return g.CanAssignToWorkspaceValue, nil
+}
+
+func (g *FakeDashboardGuardian) CanSeeLockHolder() (bool, error) {
+	return g.CanSeeLockHolderValue, nil
+}
+
+func (g *FakeDashboardGuardian) CanCleanupSnapshots() (bool, error) {
+	return g.CanCleanupSnapshotsValue, nil
+}
+
+func (g *FakeDashboardGuardian) CanApplyTemplateRecursively(folderID int64) (bool, []int64, error) {
+	return g.CanApplyTemplateRecursivelyValue, g.CanApplyTemplateRecursivelyBlocked, nil
+}
+
+func (g *FakeDashboardGuardian) CanExemptFromPolicy(policyID string) (bool, error) {
+	return g.CanExemptFromPolicyValue, nil
+}
+
+func (g *FakeDashboardGuardian) CanViewProvisioningSource() (bool, error) {
+	return g.CanViewProvisioningSourceValue, nil
+}
+
+func (g *FakeDashboardGuardian) CanCollapseToInherited() (bool, error) {
+	return g.CanCollapseToInheritedValue, nil
+}
+
+func (g *FakeDashboardGuardian) CanShareWithOrg(targetOrgID int64) (bool, error) {
+	return g.CanShareWithOrgValue, nil
+}
+
+func (g *FakeDashboardGuardian) CanPromotePanelToWidget(panelID int) (bool, error) {
+	return g.CanPromotePanelToWidgetValue, nil
+}
+
+func (g *FakeDashboardGuardian) CanEditRLS() (bool, error) {
+	return g.CanEditRLSValue, nil
+}
+
+func (g *FakeDashboardGuardian) CanRestoreFolder() (bool, []int64, error) {
+	return g.CanRestoreFolderValue, g.CanRestoreFolderBlocked, nil
+}
+
+func (g *FakeDashboardGuardian) CanChangeUID() (bool, error) {
+	return g.CanChangeUIDValue, nil
+}
+
+func (g *FakeDashboardGuardian) CanEditRetentionPolicy() (bool, error) {
+	return g.CanEditRetentionPolicyValue, nil
+}
+
+func (g *FakeDashboardGuardian) CanBulkChangeOwner(newOwnerID int64) (bool, []int64, error) {
+	return g.CanBulkChangeOwnerValue, g.CanBulkChangeOwnerBlocked, nil
+}
+
+func (g *FakeDashboardGuardian) CanSetAsOrgLanding() (bool, error) {
+	return g.CanSetAsOrgLandingValue, nil
+}
+
+func (g *FakeDashboardGuardian) CanEditBranding() (bool, error) {
+	return g.CanEditBrandingValue, nil
+}
+
+func (g *FakeDashboardGuardian) CanBulkExportFolder() (bool, []int64, error) {
+	return g.CanBulkExportFolderValue, g.CanBulkExportFolderBlocked, nil
+}
+
+func (g *FakeDashboardGuardian) CanConfigureWebhooks() (bool, error) {
+	return g.CanConfigureWebhooksValue, nil
+}
+
+func (g *FakeDashboardGuardian) CanDetachFromProvisioning() (bool, error) {
+	return g.CanDetachFromProvisioningValue, nil
+}
+
+func (g *FakeDashboardGuardian) CanGrantRole(role m.RoleType) (bool, error) {
+	return g.CanGrantRoleValue, nil
+}
+
+func (g *FakeDashboardGuardian) CanMerge(sourceDashboardID int64) (bool, error) {
+	return g.CanMergeValue, nil
+}
+
+func (g *FakeDashboardGuardian) CanConfigureSLO() (bool, error) {
+	return g.CanConfigureSLOValue, nil
+}
+
+func (g *FakeDashboardGuardian) CanBulkStarFolder() (map[int64]bool, error) {
+	return g.CanBulkStarFolderValue, nil
+}
+
+func (g *FakeDashboardGuardian) CanManageSilences() (bool, error) {
+	return g.CanManageSilencesValue, nil
+}
+
+func (g *FakeDashboardGuardian) CanCloneToOrg(targetOrgID int64, targetFolderID int64) (bool, error) {
+	return g.CanCloneToOrgValue, nil
+}
+
+func (g *FakeDashboardGuardian) CanEditDataMasking() (bool, error) {
+	return g.CanEditDataMaskingValue, nil
+}
+
+func (g *FakeDashboardGuardian) CanPinGoldenVersion(version int) (bool, error) {
+	return g.CanPinGoldenVersionValue, nil
+}
+
+func (g *FakeDashboardGuardian) CanTransferFolderAdmin(newAdminID int64) (bool, error) {
+	return g.CanTransferFolderAdminValue, nil
+}
+
+func (g *FakeDashboardGuardian) CanEditQueryTimeout() (bool, error) {
+	return g.CanEditQueryTimeoutValue, nil
+}
+
+func (g *FakeDashboardGuardian) CanEnableAnonymousAccess() (bool, error) {
+	return g.CanEnableAnonymousAccessValue, nil
+}
+
+func (g *FakeDashboardGuardian) CanChangeInheritanceMode() (bool, error) {
+	return g.CanChangeInheritanceModeValue, nil
+}
+
+func (g *FakeDashboardGuardian) CanSubscribeTeam(teamID int64) (bool, error) {
+	return g.CanSubscribeTeamValue, nil
+}
+
+func (g *FakeDashboardGuardian) CanConfigureAuditLogging() (bool, error) {
+	return g.CanConfigureAuditLoggingValue, nil
+}
+
+func (g *FakeDashboardGuardian) CanImportAcl(exported []byte) (bool, error) {
+	return g.CanImportAclValue, nil
+}
+
+func (g *FakeDashboardGuardian) CanAddToNavSection(sectionID string) (bool, error) {
+	return g.CanAddToNavSectionValue, nil
+}
+
+func (g *FakeDashboardGuardian) CanSaveInFolder(folderID int64) (bool, error) {
+	return g.CanSaveInFolderValue, nil
+}
+
+func (g *FakeDashboardGuardian) CanDeprecate() (bool, error) {
+	return g.CanDeprecateValue, nil
+}
+
+func (g *FakeDashboardGuardian) orError(ok bool, permission m.PermissionType) error {
+	if ok {
+		return nil
+	}
+
+	return PermissionDeniedError{DashboardId: g.DashId, Requirement: permission.String()}
+}
+
+func (g *FakeDashboardGuardian) CanViewOrError() error {
+	return g.orError(g.CanViewValue, m.PERMISSION_VIEW)
+}
+
+func (g *FakeDashboardGuardian) CanEditOrError() error {
+	return g.orError(g.CanEditValue, m.PERMISSION_EDIT)
+}
+
+func (g *FakeDashboardGuardian) CanSaveOrError() error {
+	return g.orError(g.CanSaveValue, m.PERMISSION_EDIT)
+}
+
+func (g *FakeDashboardGuardian) CanAdminOrError() error {
+	return g.orError(g.CanAdminValue, m.PERMISSION_ADMIN)
+}
+
+func (g *FakeDashboardGuardian) CanEditDefaultVariables() (bool, error) {
+	return g.CanEditDefaultVariablesValue, nil
+}
+
+func (g *FakeDashboardGuardian) FilterDashboardListResults(dashboardIDs []int64) ([]int64, error) {
+	return g.FilterDashboardListResultsValue, nil
+}
+
+func (g *FakeDashboardGuardian) EvaluateForUser(user *m.SignedInUser, permission m.PermissionType) (bool, error) {
+	return g.EvaluateForUserValue, nil
+}
+
+func (g *FakeDashboardGuardian) CanEditContactPointOverrides() (bool, error) {
+	return g.CanEditContactPointOverridesValue, nil
+}
+
+func (g *FakeDashboardGuardian) CanDeleteFolderContents() (bool, error) {
+	return g.CanDeleteFolderContentsValue, nil
+}
+
+func (g *FakeDashboardGuardian) CanPromoteToSharedSpace() (bool, error) {
+	return g.CanPromoteToSharedSpaceValue, nil
+}
+
+func (g *FakeDashboardGuardian) CanEditFeatureFlags() (bool, error) {
+	return g.CanEditFeatureFlagsValue, nil
+}
+
+func (g *FakeDashboardGuardian) ExplainAccess(permission m.PermissionType) (*AccessExplanation, error) {
+	return g.ExplainAccessValue, nil
+}
+
+func (g *FakeDashboardGuardian) CanEditPIIRedaction() (bool, error) {
+	return g.CanEditPIIRedactionValue, nil
+}
+
+func (g *FakeDashboardGuardian) CanMoveOutOfLockedFolder(newFolderID int64) (bool, error) {
+	return g.CanMoveOutOfLockedFolderValue, nil
+}
+
+func (g *FakeDashboardGuardian) CanEditExportWatermark() (bool, error) {
+	return g.CanEditExportWatermarkValue, nil
+}
+
+func (g *FakeDashboardGuardian) CanEditEmbedAllowlist() (bool, error) {
+	return g.CanEditEmbedAllowlistValue, nil
+}
+
+func (g *FakeDashboardGuardian) CanOverrideInheritanceTemporarily() (bool, error) {
+	return g.CanOverrideInheritanceTemporarilyValue, nil
+}
+
+func (g *FakeDashboardGuardian) CanStar() (bool, error) {
+	return g.CanStarValue, nil
+}
+
+func (g *FakeDashboardGuardian) CanEditDatasourceFailover() (bool, error) {
+	return g.CanEditDatasourceFailoverValue, nil
 }
 
 func MockDashboardGuardian(mock *FakeDashboardGuardian) {
@@ -1,11 +1,19 @@
 package guardian
 
 import (
+	"errors"
 	"fmt"
 	"runtime"
 	"testing"
 
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/metrics"
 	m "github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/search"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
 	. "github.com/smartystreets/goconvey/convey"
 )
 
@@ -15,6 +23,8 @@ var (
 	dashboardID        = int64(1)
 	parentFolderID     = int64(2)
 	childDashboardID   = int64(3)
+	otherDashboardID   = int64(4)
+	otherFolderID      = int64(5)
 	userID             = int64(1)
 	otherUserID        = int64(2)
 	teamID             = int64(1)
@@ -176,6 +186,3308 @@ func TestGuardianViewer(t *testing.T) {
 	})
 }
 
+func TestGuardianCanSetSharedTimeRange(t *testing.T) {
+	Convey("Guardian shared time range tests", t, func() {
+		Convey("Given user is admin of the dashboard", func() {
+			g := newTestGuardian(m.ROLE_EDITOR, map[int64][]*m.DashboardAclInfoDTO{
+				dashboardID: {toDto(newDefaultUserPermission(dashboardID, m.PERMISSION_ADMIN))},
+			})
+
+			Convey("Should be allowed to set shared time range", func() {
+				ok, err := g.CanSetSharedTimeRange()
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeTrue)
+			})
+		})
+
+		Convey("Given user can only edit the dashboard", func() {
+			g := newTestGuardian(m.ROLE_EDITOR, map[int64][]*m.DashboardAclInfoDTO{
+				dashboardID: {toDto(newDefaultUserPermission(dashboardID, m.PERMISSION_EDIT))},
+			})
+
+			Convey("Should not be allowed to set shared time range", func() {
+				ok, err := g.CanSetSharedTimeRange()
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeFalse)
+			})
+		})
+	})
+}
+
+func TestGuardianCanDeleteWithDependencies(t *testing.T) {
+	Convey("Guardian delete with dependencies tests", t, func() {
+		Convey("Given user can edit a dashboard referenced by an alert", func() {
+			g := newTestGuardian(m.ROLE_EDITOR, map[int64][]*m.DashboardAclInfoDTO{
+				dashboardID: {toDto(newDefaultUserPermission(dashboardID, m.PERMISSION_EDIT))},
+			})
+
+			bus.AddHandler("test", func(query *m.GetAlertsQuery) error {
+				if len(query.DashboardIDs) == 1 && query.DashboardIDs[0] == dashboardID {
+					query.Result = []*m.AlertListItemDTO{{Id: 5, DashboardId: dashboardID}}
+				}
+				return nil
+			})
+
+			bus.AddHandler("test", func(query *m.GetPlaylistsByDashboardIdQuery) error {
+				if query.DashboardId == dashboardID && query.OrgId == orgID {
+					query.Result = m.Playlists{{Id: 7, Name: "Office", OrgId: orgID}}
+				}
+				return nil
+			})
+
+			Convey("Should be allowed to delete and return the alert and playlist as blockers", func() {
+				ok, blockers, err := g.CanDeleteWithDependencies()
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeTrue)
+				So(blockers, ShouldResemble, []string{"alert:5", "playlist:7"})
+			})
+		})
+
+		Convey("Given user can only view the dashboard", func() {
+			g := newTestGuardian(m.ROLE_VIEWER, map[int64][]*m.DashboardAclInfoDTO{
+				dashboardID: {toDto(newDefaultUserPermission(dashboardID, m.PERMISSION_VIEW))},
+			})
+
+			Convey("Should not be allowed to delete", func() {
+				ok, blockers, err := g.CanDeleteWithDependencies()
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeFalse)
+				So(blockers, ShouldBeEmpty)
+			})
+		})
+	})
+}
+
+func TestGuardianCanCreateWithDefaultTemplate(t *testing.T) {
+	Convey("Guardian create with default template tests", t, func() {
+		Convey("Given user can edit a folder whose permissions only grant edit", func() {
+			g := newTestGuardian(m.ROLE_EDITOR, map[int64][]*m.DashboardAclInfoDTO{
+				parentFolderID: {
+					toDto(newDefaultUserPermission(parentFolderID, m.PERMISSION_EDIT)),
+					toDto(newDefaultTeamPermission(parentFolderID, m.PERMISSION_VIEW)),
+				},
+			})
+
+			Convey("Should be allowed to create with the default template", func() {
+				ok, err := g.CanCreateWithDefaultTemplate(parentFolderID)
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeTrue)
+			})
+		})
+
+		Convey("Given user can edit a folder whose permissions grant admin to a team", func() {
+			g := newTestGuardian(m.ROLE_EDITOR, map[int64][]*m.DashboardAclInfoDTO{
+				parentFolderID: {
+					toDto(newDefaultUserPermission(parentFolderID, m.PERMISSION_EDIT)),
+					toDto(newCustomTeamPermission(parentFolderID, otherTeamID, m.PERMISSION_ADMIN)),
+				},
+			})
+
+			Convey("Should not be allowed to create with the default template", func() {
+				ok, err := g.CanCreateWithDefaultTemplate(parentFolderID)
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeFalse)
+			})
+		})
+
+		Convey("Given user can only view the folder", func() {
+			g := newTestGuardian(m.ROLE_EDITOR, map[int64][]*m.DashboardAclInfoDTO{
+				parentFolderID: {toDto(newDefaultUserPermission(parentFolderID, m.PERMISSION_VIEW))},
+			})
+
+			Convey("Should not be allowed to create", func() {
+				ok, err := g.CanCreateWithDefaultTemplate(parentFolderID)
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeFalse)
+			})
+		})
+	})
+}
+
+func TestGuardianExportAcl(t *testing.T) {
+	Convey("Guardian acl export tests", t, func() {
+		Convey("Given user is admin of the dashboard", func() {
+			g := newTestGuardian(m.ROLE_EDITOR, map[int64][]*m.DashboardAclInfoDTO{
+				dashboardID: {toDto(newDefaultUserPermission(dashboardID, m.PERMISSION_ADMIN))},
+			})
+
+			Convey("Should be allowed to export the acl", func() {
+				ok, err := g.CanExportAcl()
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeTrue)
+
+				acl, err := g.ExportAcl()
+				So(err, ShouldBeNil)
+				So(acl, ShouldHaveLength, 1)
+			})
+		})
+
+		Convey("Given user can only view the dashboard", func() {
+			g := newTestGuardian(m.ROLE_VIEWER, map[int64][]*m.DashboardAclInfoDTO{
+				dashboardID: {toDto(newDefaultUserPermission(dashboardID, m.PERMISSION_VIEW))},
+			})
+
+			Convey("Should be denied the acl export", func() {
+				ok, err := g.CanExportAcl()
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeFalse)
+
+				acl, err := g.ExportAcl()
+				So(err, ShouldResemble, PermissionDeniedError{DashboardId: dashboardID, Requirement: "Admin"})
+				So(acl, ShouldBeNil)
+			})
+		})
+	})
+}
+
+func TestGuardianCanEmbedInto(t *testing.T) {
+	Convey("Guardian embed into dashboard tests", t, func() {
+		Convey("Given user can view the dashboard and edit the parent", func() {
+			g := newTestGuardian(m.ROLE_VIEWER, map[int64][]*m.DashboardAclInfoDTO{
+				dashboardID:      {toDto(newDefaultUserPermission(dashboardID, m.PERMISSION_VIEW))},
+				otherDashboardID: {toDto(newDefaultUserPermission(otherDashboardID, m.PERMISSION_EDIT))},
+			})
+
+			Convey("Should be allowed to embed", func() {
+				ok, err := g.CanEmbedInto(otherDashboardID)
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeTrue)
+			})
+		})
+
+		Convey("Given user can view the dashboard but not edit the parent", func() {
+			g := newTestGuardian(m.ROLE_VIEWER, map[int64][]*m.DashboardAclInfoDTO{
+				dashboardID:      {toDto(newDefaultUserPermission(dashboardID, m.PERMISSION_VIEW))},
+				otherDashboardID: {toDto(newDefaultUserPermission(otherDashboardID, m.PERMISSION_VIEW))},
+			})
+
+			Convey("Should be denied with the parent edit requirement", func() {
+				ok, err := g.CanEmbedInto(otherDashboardID)
+				So(err, ShouldResemble, PermissionDeniedError{DashboardId: otherDashboardID, Requirement: "Edit"})
+				So(ok, ShouldBeFalse)
+			})
+		})
+
+		Convey("Given user cannot view the dashboard", func() {
+			g := newTestGuardian(m.ROLE_VIEWER, map[int64][]*m.DashboardAclInfoDTO{
+				otherDashboardID: {toDto(newDefaultUserPermission(otherDashboardID, m.PERMISSION_EDIT))},
+			})
+
+			Convey("Should be denied with the view requirement", func() {
+				ok, err := g.CanEmbedInto(otherDashboardID)
+				So(err, ShouldResemble, PermissionDeniedError{DashboardId: dashboardID, Requirement: "View"})
+				So(ok, ShouldBeFalse)
+			})
+		})
+	})
+}
+
+func TestGuardianCanRestoreVersion(t *testing.T) {
+	Convey("Guardian restore version tests", t, func() {
+		Convey("Given user can edit the dashboard", func() {
+			g := newTestGuardian(m.ROLE_EDITOR, map[int64][]*m.DashboardAclInfoDTO{
+				dashboardID: {toDto(newDefaultUserPermission(dashboardID, m.PERMISSION_EDIT))},
+			})
+			setupTestVersions()
+
+			Convey("Should be allowed to restore an existing version", func() {
+				ok, err := g.CanRestoreVersion(2)
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeTrue)
+			})
+
+			Convey("Should return not found for a missing version", func() {
+				ok, err := g.CanRestoreVersion(3)
+				So(err, ShouldEqual, m.ErrDashboardVersionNotFound)
+				So(ok, ShouldBeFalse)
+			})
+		})
+
+		Convey("Given user can only view the dashboard", func() {
+			g := newTestGuardian(m.ROLE_VIEWER, map[int64][]*m.DashboardAclInfoDTO{
+				dashboardID: {toDto(newDefaultUserPermission(dashboardID, m.PERMISSION_VIEW))},
+			})
+			setupTestVersions()
+
+			Convey("Should not be allowed to restore", func() {
+				ok, err := g.CanRestoreVersion(2)
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeFalse)
+			})
+		})
+	})
+}
+
+func TestGuardianCanAckAlerts(t *testing.T) {
+	Convey("Guardian acknowledge alerts tests", t, func() {
+		Convey("Given user can view the dashboard", func() {
+			g := newTestGuardian(m.ROLE_VIEWER, map[int64][]*m.DashboardAclInfoDTO{
+				dashboardID: {toDto(newDefaultUserPermission(dashboardID, m.PERMISSION_VIEW))},
+			})
+
+			Convey("Should be allowed to acknowledge alerts", func() {
+				ok, err := g.CanAckAlerts()
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeTrue)
+			})
+		})
+
+		Convey("Given user has no access to the dashboard", func() {
+			g := newTestGuardian(m.ROLE_VIEWER, map[int64][]*m.DashboardAclInfoDTO{})
+
+			Convey("Should not be allowed to acknowledge alerts", func() {
+				ok, err := g.CanAckAlerts()
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeFalse)
+			})
+		})
+	})
+}
+
+func TestGuardianCanTrashAndPurge(t *testing.T) {
+	Convey("Guardian trash and purge tests", t, func() {
+		Convey("Given user is admin of the dashboard", func() {
+			g := newTestGuardian(m.ROLE_EDITOR, map[int64][]*m.DashboardAclInfoDTO{
+				dashboardID: {toDto(newDefaultUserPermission(dashboardID, m.PERMISSION_ADMIN))},
+			})
+
+			Convey("Should be allowed to trash and purge", func() {
+				canTrash, err := g.CanTrash()
+				So(err, ShouldBeNil)
+				So(canTrash, ShouldBeTrue)
+
+				canPurge, err := g.CanPurge()
+				So(err, ShouldBeNil)
+				So(canPurge, ShouldBeTrue)
+			})
+		})
+
+		Convey("Given user can edit the dashboard", func() {
+			g := newTestGuardian(m.ROLE_EDITOR, map[int64][]*m.DashboardAclInfoDTO{
+				dashboardID: {toDto(newDefaultUserPermission(dashboardID, m.PERMISSION_EDIT))},
+			})
+
+			Convey("Should be allowed to trash but not purge", func() {
+				canTrash, err := g.CanTrash()
+				So(err, ShouldBeNil)
+				So(canTrash, ShouldBeTrue)
+
+				canPurge, err := g.CanPurge()
+				So(err, ShouldBeNil)
+				So(canPurge, ShouldBeFalse)
+			})
+		})
+
+		Convey("Given user can only view the dashboard", func() {
+			g := newTestGuardian(m.ROLE_VIEWER, map[int64][]*m.DashboardAclInfoDTO{
+				dashboardID: {toDto(newDefaultUserPermission(dashboardID, m.PERMISSION_VIEW))},
+			})
+
+			Convey("Should not be allowed to trash", func() {
+				canTrash, err := g.CanTrash()
+				So(err, ShouldBeNil)
+				So(canTrash, ShouldBeFalse)
+			})
+		})
+	})
+}
+
+func TestGuardianCanDuplicateFolder(t *testing.T) {
+	Convey("Guardian duplicate folder tests", t, func() {
+		acl := map[int64][]*m.DashboardAclInfoDTO{
+			0: {
+				toDto(newEditorRolePermission(defaultDashboardID, m.PERMISSION_EDIT)),
+				toDto(newViewerRolePermission(defaultDashboardID, m.PERMISSION_VIEW)),
+			},
+			parentFolderID: {toDto(newDefaultUserPermission(parentFolderID, m.PERMISSION_VIEW))},
+		}
+
+		Convey("Given user can view every dashboard in the folder", func() {
+			g := newTestFolderGuardian(m.ROLE_EDITOR, acl)
+			setupTestFolderChildren([]*m.DashboardPermissionForUser{
+				{DashboardId: childDashboardID, Permission: m.PERMISSION_VIEW},
+				{DashboardId: otherDashboardID, Permission: m.PERMISSION_EDIT},
+			})
+
+			Convey("Should be allowed to duplicate the folder", func() {
+				ok, err := g.CanDuplicateFolder(0)
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeTrue)
+			})
+		})
+
+		Convey("Given the folder contains a dashboard the user cannot view", func() {
+			g := newTestFolderGuardian(m.ROLE_EDITOR, acl)
+			setupTestFolderChildren([]*m.DashboardPermissionForUser{
+				{DashboardId: childDashboardID, Permission: m.PERMISSION_VIEW},
+			})
+
+			Convey("Should not be allowed to duplicate the folder", func() {
+				ok, err := g.CanDuplicateFolder(0)
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeFalse)
+			})
+		})
+
+		Convey("Given user cannot create in the target", func() {
+			g := newTestFolderGuardian(m.ROLE_VIEWER, acl)
+			setupTestFolderChildren([]*m.DashboardPermissionForUser{
+				{DashboardId: childDashboardID, Permission: m.PERMISSION_VIEW},
+				{DashboardId: otherDashboardID, Permission: m.PERMISSION_VIEW},
+			})
+
+			Convey("Should not be allowed to duplicate the folder", func() {
+				ok, err := g.CanDuplicateFolder(0)
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeFalse)
+			})
+		})
+	})
+}
+
+func TestGuardianCanManageDatasourcePropagation(t *testing.T) {
+	Convey("Guardian data source propagation tests", t, func() {
+		Convey("Given user is org admin", func() {
+			g := newTestGuardian(m.ROLE_ADMIN, map[int64][]*m.DashboardAclInfoDTO{})
+
+			Convey("Should be allowed to manage data source propagation", func() {
+				ok, err := g.CanManageDatasourcePropagation()
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeTrue)
+			})
+		})
+
+		Convey("Given user is admin of the dashboard but not org admin", func() {
+			g := newTestGuardian(m.ROLE_EDITOR, map[int64][]*m.DashboardAclInfoDTO{
+				dashboardID: {toDto(newDefaultUserPermission(dashboardID, m.PERMISSION_ADMIN))},
+			})
+			setupDashboard := func(data map[string]interface{}) {
+				bus.AddHandler("test", func(query *m.GetDashboardQuery) error {
+					query.Result = &m.Dashboard{Id: dashboardID, OrgId: orgID, Data: simplejson.NewFromAny(data)}
+					return nil
+				})
+			}
+
+			Convey("Should be denied naming the first data source of the panels", func() {
+				setupDashboard(map[string]interface{}{
+					"panels": []interface{}{
+						map[string]interface{}{"type": "text"},
+						map[string]interface{}{
+							"type":   "row",
+							"panels": []interface{}{map[string]interface{}{"targets": []interface{}{map[string]interface{}{"datasource": "prod-db"}}}},
+						},
+						map[string]interface{}{"datasource": "logs"},
+					},
+				})
+
+				ok, err := g.CanManageDatasourcePropagation()
+				So(err, ShouldResemble, PermissionDeniedError{DashboardId: dashboardID, Datasource: "prod-db", Requirement: "org admin"})
+				So(ok, ShouldBeFalse)
+			})
+
+			Convey("Should be denied with the org admin requirement when no data source is named", func() {
+				setupDashboard(map[string]interface{}{"panels": []interface{}{map[string]interface{}{"type": "graph"}}})
+
+				ok, err := g.CanManageDatasourcePropagation()
+				So(err, ShouldResemble, PermissionDeniedError{DashboardId: dashboardID, Requirement: "org admin"})
+				So(ok, ShouldBeFalse)
+			})
+		})
+
+		Convey("Given user can only edit the dashboard", func() {
+			g := newTestGuardian(m.ROLE_EDITOR, map[int64][]*m.DashboardAclInfoDTO{
+				dashboardID: {toDto(newDefaultUserPermission(dashboardID, m.PERMISSION_EDIT))},
+			})
+
+			Convey("Should be denied with the dashboard admin requirement", func() {
+				ok, err := g.CanManageDatasourcePropagation()
+				So(err, ShouldResemble, PermissionDeniedError{DashboardId: dashboardID, Requirement: "Admin"})
+				So(ok, ShouldBeFalse)
+			})
+		})
+	})
+}
+
+func TestGuardianBulkTagFolder(t *testing.T) {
+	Convey("Guardian bulk tag folder tests", t, func() {
+		Convey("Given user can edit the folder", func() {
+			g := newTestFolderGuardian(m.ROLE_EDITOR, map[int64][]*m.DashboardAclInfoDTO{
+				parentFolderID: {toDto(newDefaultUserPermission(parentFolderID, m.PERMISSION_EDIT))},
+			})
+			setupTestFolderChildren([]*m.DashboardPermissionForUser{
+				{DashboardId: childDashboardID, Permission: m.PERMISSION_EDIT},
+				{DashboardId: otherDashboardID, Permission: m.PERMISSION_EDIT},
+			})
+
+			Convey("Should be allowed to bulk tag the folder", func() {
+				ok, err := g.CanBulkTagFolder()
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeTrue)
+
+				editable, err := g.GetEditableFolderDashboards()
+				So(err, ShouldBeNil)
+				So(editable, ShouldResemble, []int64{childDashboardID, otherDashboardID})
+			})
+		})
+
+		Convey("Given user can only edit one dashboard in the folder", func() {
+			g := newTestFolderGuardian(m.ROLE_VIEWER, map[int64][]*m.DashboardAclInfoDTO{
+				parentFolderID: {toDto(newDefaultUserPermission(parentFolderID, m.PERMISSION_VIEW))},
+			})
+			setupTestFolderChildren([]*m.DashboardPermissionForUser{
+				{DashboardId: childDashboardID, Permission: m.PERMISSION_VIEW},
+				{DashboardId: otherDashboardID, Permission: m.PERMISSION_EDIT},
+			})
+
+			Convey("Should not be allowed to bulk tag the folder", func() {
+				ok, err := g.CanBulkTagFolder()
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeFalse)
+			})
+
+			Convey("Should list the editable dashboard", func() {
+				editable, err := g.GetEditableFolderDashboards()
+				So(err, ShouldBeNil)
+				So(editable, ShouldResemble, []int64{otherDashboardID})
+			})
+		})
+
+		Convey("Given a folder only the owner has access to", func() {
+			f := setupTestRestrictedFolder(t)
+
+			Convey("Should only list the dashboard shared with the viewer", func() {
+				editable, err := New(f.folderID, orgID, f.viewer).GetEditableFolderDashboards()
+				So(err, ShouldBeNil)
+				So(editable, ShouldResemble, []int64{f.sharedID})
+			})
+
+			Convey("Should list all dashboards for the owner", func() {
+				editable, err := New(f.folderID, orgID, f.owner).GetEditableFolderDashboards()
+				So(err, ShouldBeNil)
+				So(editable, ShouldResemble, []int64{f.childID, f.sharedID})
+			})
+		})
+	})
+}
+
+func TestGuardianCanShareToChannel(t *testing.T) {
+	Convey("Guardian share to channel tests", t, func() {
+		Convey("Given editor can view the dashboard", func() {
+			g := newTestGuardian(m.ROLE_EDITOR, map[int64][]*m.DashboardAclInfoDTO{
+				dashboardID: {toDto(newDefaultUserPermission(dashboardID, m.PERMISSION_VIEW))},
+			})
+
+			Convey("Should be allowed to share to a channel", func() {
+				ok, err := g.CanShareToChannel("slack")
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeTrue)
+			})
+		})
+
+		Convey("Given viewer can view the dashboard", func() {
+			g := newTestGuardian(m.ROLE_VIEWER, map[int64][]*m.DashboardAclInfoDTO{
+				dashboardID: {toDto(newDefaultUserPermission(dashboardID, m.PERMISSION_VIEW))},
+			})
+
+			Convey("Should be denied with the channel requirement", func() {
+				ok, err := g.CanShareToChannel("slack")
+				So(err, ShouldResemble, PermissionDeniedError{DashboardId: dashboardID, Requirement: "editor role"})
+				So(ok, ShouldBeFalse)
+			})
+		})
+
+		Convey("Given editor cannot view the dashboard", func() {
+			g := newTestGuardian(m.ROLE_EDITOR, map[int64][]*m.DashboardAclInfoDTO{})
+
+			Convey("Should be denied with the view requirement", func() {
+				ok, err := g.CanShareToChannel("slack")
+				So(err, ShouldResemble, PermissionDeniedError{DashboardId: dashboardID, Requirement: "View"})
+				So(ok, ShouldBeFalse)
+			})
+		})
+	})
+}
+
+func TestGuardianCanCreateFromTemplate(t *testing.T) {
+	Convey("Guardian create from template tests", t, func() {
+		acl := map[int64][]*m.DashboardAclInfoDTO{
+			parentFolderID: {toDto(newEditorRolePermission(parentFolderID, m.PERMISSION_EDIT))},
+		}
+
+		Convey("Given editor can create in the folder and query all data sources", func() {
+			g := newTestGuardian(m.ROLE_EDITOR, acl)
+			setupTestDatasources("graphite", "prometheus")
+
+			Convey("Should be allowed to create from template", func() {
+				ok, err := g.CanCreateFromTemplate(parentFolderID, []string{"graphite", "prometheus"})
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeTrue)
+			})
+		})
+
+		Convey("Given editor cannot query one of the data sources", func() {
+			g := newTestGuardian(m.ROLE_EDITOR, acl)
+			setupTestDatasources("graphite")
+
+			Convey("Should be denied with the data source requirement", func() {
+				ok, err := g.CanCreateFromTemplate(parentFolderID, []string{"graphite", "prometheus"})
+				So(err, ShouldResemble, PermissionDeniedError{DashboardId: parentFolderID, Datasource: "prometheus", Requirement: "Query"})
+				So(ok, ShouldBeFalse)
+			})
+		})
+
+		Convey("Given viewer cannot create in the folder", func() {
+			g := newTestGuardian(m.ROLE_VIEWER, acl)
+			setupTestDatasources("graphite", "prometheus")
+
+			Convey("Should be denied with the folder requirement", func() {
+				ok, err := g.CanCreateFromTemplate(parentFolderID, []string{"graphite"})
+				So(err, ShouldResemble, PermissionDeniedError{DashboardId: parentFolderID, Requirement: "Edit"})
+				So(ok, ShouldBeFalse)
+			})
+		})
+
+		Convey("Given no data source permission handler", func() {
+			g := newTestGuardian(m.ROLE_EDITOR, acl)
+			bus.AddHandler("test", func(query *m.GetDataSourceByNameQuery) error {
+				query.Result = &m.DataSource{Name: query.Name, OrgId: query.OrgId}
+				return nil
+			})
+
+			Convey("Should allow every data source", func() {
+				ok, err := g.CanCreateFromTemplate(parentFolderID, []string{"graphite"})
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeTrue)
+			})
+		})
+	})
+}
+
+func TestGuardianIsSoleAdmin(t *testing.T) {
+	Convey("Guardian sole admin tests", t, func() {
+		Convey("Given user is the only admin of the dashboard", func() {
+			g := newTestGuardian(m.ROLE_EDITOR, map[int64][]*m.DashboardAclInfoDTO{
+				dashboardID: {
+					toDto(newDefaultUserPermission(dashboardID, m.PERMISSION_ADMIN)),
+					toDto(newEditorRolePermission(dashboardID, m.PERMISSION_EDIT)),
+				},
+			})
+
+			Convey("Should be sole admin", func() {
+				ok, err := g.IsSoleAdmin()
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeTrue)
+			})
+		})
+
+		Convey("Given user shares admin with another user", func() {
+			g := newTestGuardian(m.ROLE_EDITOR, map[int64][]*m.DashboardAclInfoDTO{
+				dashboardID: {
+					toDto(newDefaultUserPermission(dashboardID, m.PERMISSION_ADMIN)),
+					toDto(newCustomUserPermission(dashboardID, otherUserID, m.PERMISSION_ADMIN)),
+				},
+			})
+
+			Convey("Should not be sole admin", func() {
+				ok, err := g.IsSoleAdmin()
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeFalse)
+			})
+		})
+
+		Convey("Given admin is also inherited from the folder", func() {
+			inherited := toDto(newDefaultTeamPermission(parentFolderID, m.PERMISSION_ADMIN))
+			inherited.Inherited = true
+			g := newTestGuardian(m.ROLE_EDITOR, map[int64][]*m.DashboardAclInfoDTO{
+				dashboardID: {toDto(newDefaultUserPermission(dashboardID, m.PERMISSION_ADMIN)), inherited},
+			})
+
+			Convey("Should not be sole admin", func() {
+				ok, err := g.IsSoleAdmin()
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeFalse)
+			})
+		})
+
+		Convey("Given user is not admin of the dashboard", func() {
+			g := newTestGuardian(m.ROLE_EDITOR, map[int64][]*m.DashboardAclInfoDTO{
+				dashboardID: {toDto(newDefaultUserPermission(dashboardID, m.PERMISSION_EDIT))},
+			})
+
+			Convey("Should not be sole admin", func() {
+				ok, err := g.IsSoleAdmin()
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeFalse)
+			})
+		})
+	})
+}
+
+func TestGuardianCanPauseAlerts(t *testing.T) {
+	Convey("Guardian pause alerts tests", t, func() {
+		Convey("Given user can edit the dashboard", func() {
+			g := newTestGuardian(m.ROLE_EDITOR, map[int64][]*m.DashboardAclInfoDTO{
+				dashboardID: {toDto(newDefaultUserPermission(dashboardID, m.PERMISSION_EDIT))},
+			})
+
+			Convey("Should be allowed to pause alerts", func() {
+				ok, err := g.CanPauseAlerts()
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeTrue)
+			})
+		})
+
+		Convey("Given user can only view the dashboard", func() {
+			g := newTestGuardian(m.ROLE_VIEWER, map[int64][]*m.DashboardAclInfoDTO{
+				dashboardID: {toDto(newDefaultUserPermission(dashboardID, m.PERMISSION_VIEW))},
+			})
+
+			Convey("Should not be allowed to pause alerts", func() {
+				ok, err := g.CanPauseAlerts()
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeFalse)
+			})
+		})
+	})
+}
+
+func TestGuardianCanAttachToIncident(t *testing.T) {
+	Convey("Guardian attach to incident tests", t, func() {
+		Convey("Given editor can view the dashboard", func() {
+			g := newTestGuardian(m.ROLE_EDITOR, map[int64][]*m.DashboardAclInfoDTO{
+				dashboardID: {toDto(newDefaultUserPermission(dashboardID, m.PERMISSION_VIEW))},
+			})
+
+			Convey("Should be allowed to attach to an incident", func() {
+				ok, err := g.CanAttachToIncident("INC-1")
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeTrue)
+			})
+		})
+
+		Convey("Given viewer can view the dashboard", func() {
+			g := newTestGuardian(m.ROLE_VIEWER, map[int64][]*m.DashboardAclInfoDTO{
+				dashboardID: {toDto(newDefaultUserPermission(dashboardID, m.PERMISSION_VIEW))},
+			})
+
+			Convey("Should be denied with the incident requirement", func() {
+				ok, err := g.CanAttachToIncident("INC-1")
+				So(err, ShouldResemble, PermissionDeniedError{DashboardId: dashboardID, Requirement: "editor role"})
+				So(ok, ShouldBeFalse)
+			})
+		})
+
+		Convey("Given editor cannot view the dashboard", func() {
+			g := newTestGuardian(m.ROLE_EDITOR, map[int64][]*m.DashboardAclInfoDTO{})
+
+			Convey("Should be denied with the view requirement", func() {
+				ok, err := g.CanAttachToIncident("INC-1")
+				So(err, ShouldResemble, PermissionDeniedError{DashboardId: dashboardID, Requirement: "View"})
+				So(ok, ShouldBeFalse)
+			})
+		})
+	})
+}
+
+func TestGuardianCanSetAsTeamDefault(t *testing.T) {
+	Convey("Guardian set as team default tests", t, func() {
+		setupTeamMember := func(permission m.PermissionType) {
+			bus.AddHandler("test", func(query *m.GetTeamMembersQuery) error {
+				query.Result = []*m.TeamMemberDTO{{OrgId: orgID, TeamId: teamID, UserId: userID, Permission: permission}}
+				return nil
+			})
+		}
+
+		acl := map[int64][]*m.DashboardAclInfoDTO{
+			dashboardID: {toDto(newDefaultUserPermission(dashboardID, m.PERMISSION_VIEW))},
+		}
+
+		Convey("Given user is an admin of the team", func() {
+			g := newTestGuardian(m.ROLE_EDITOR, acl)
+			setupTeamMember(m.PERMISSION_ADMIN)
+
+			Convey("Should be allowed to set the dashboard as team default", func() {
+				ok, err := g.CanSetAsTeamDefault(teamID)
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeTrue)
+			})
+		})
+
+		Convey("Given user is a regular member of the team", func() {
+			g := newTestGuardian(m.ROLE_EDITOR, acl)
+			setupTeamMember(0)
+
+			Convey("Should be denied with the team admin requirement", func() {
+				ok, err := g.CanSetAsTeamDefault(teamID)
+				So(err, ShouldResemble, PermissionDeniedError{DashboardId: dashboardID, Requirement: "team admin"})
+				So(ok, ShouldBeFalse)
+			})
+		})
+
+		Convey("Given team admin cannot view the dashboard", func() {
+			g := newTestGuardian(m.ROLE_EDITOR, map[int64][]*m.DashboardAclInfoDTO{})
+			setupTeamMember(m.PERMISSION_ADMIN)
+
+			Convey("Should be denied with the view requirement", func() {
+				ok, err := g.CanSetAsTeamDefault(teamID)
+				So(err, ShouldResemble, PermissionDeniedError{DashboardId: dashboardID, Requirement: "View"})
+				So(ok, ShouldBeFalse)
+			})
+		})
+	})
+}
+
+func TestGuardianAccessRequests(t *testing.T) {
+	Convey("Guardian access request tests", t, func() {
+		Convey("Given user has admin permission", func() {
+			g := newTestGuardian(m.ROLE_VIEWER, map[int64][]*m.DashboardAclInfoDTO{
+				dashboardID: {toDto(newDefaultUserPermission(dashboardID, m.PERMISSION_ADMIN))},
+			})
+
+			Convey("Should be allowed to configure access requests", func() {
+				ok, err := g.CanConfigureAccessRequests()
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeTrue)
+			})
+		})
+
+		Convey("Given user has edit permission", func() {
+			g := newTestGuardian(m.ROLE_VIEWER, map[int64][]*m.DashboardAclInfoDTO{
+				dashboardID: {toDto(newDefaultUserPermission(dashboardID, m.PERMISSION_EDIT))},
+			})
+
+			Convey("Should not be allowed to configure access requests", func() {
+				ok, err := g.CanConfigureAccessRequests()
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeFalse)
+			})
+		})
+
+		Convey("Given org member without any permission", func() {
+			g := newTestGuardian(m.ROLE_VIEWER, map[int64][]*m.DashboardAclInfoDTO{})
+
+			Convey("Should be allowed to request edit access", func() {
+				ok, err := g.CanRequestAccess(m.PERMISSION_EDIT)
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeTrue)
+			})
+		})
+
+		Convey("Given anonymous user", func() {
+			bus.ClearBusHandlers()
+			g := New(dashboardID, orgID, &m.SignedInUser{OrgId: orgID, OrgRole: m.ROLE_VIEWER, IsAnonymous: true})
+
+			Convey("Should not be allowed to request access", func() {
+				ok, err := g.CanRequestAccess(m.PERMISSION_VIEW)
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeFalse)
+			})
+		})
+	})
+}
+
+func TestGuardianCanApproveAccessRequest(t *testing.T) {
+	Convey("Guardian approve access request tests", t, func() {
+		setupOrgMembers := func() {
+			bus.AddHandler("test", func(query *m.GetSignedInUserQuery) error {
+				query.Result = &m.SignedInUser{UserId: query.UserId, OrgId: query.OrgId, OrgRole: m.ROLE_VIEWER}
+				if query.UserId != otherUserID {
+					query.Result.OrgId = -1
+					query.Result.OrgRole = ""
+				}
+				return nil
+			})
+		}
+
+		Convey("Given user has admin permission", func() {
+			g := newTestGuardian(m.ROLE_VIEWER, map[int64][]*m.DashboardAclInfoDTO{
+				dashboardID: {toDto(newDefaultUserPermission(dashboardID, m.PERMISSION_ADMIN))},
+			})
+			setupOrgMembers()
+
+			Convey("Should be allowed to approve an edit request", func() {
+				ok, err := g.CanApproveAccessRequest(otherUserID, m.PERMISSION_EDIT)
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeTrue)
+			})
+
+			Convey("Should be denied to approve an unknown level", func() {
+				for _, level := range []m.PermissionType{0, 3, m.PERMISSION_ADMIN << 1} {
+					ok, err := g.CanApproveAccessRequest(otherUserID, level)
+					So(err, ShouldEqual, ErrGuardianGrantExceedsOwn)
+					So(ok, ShouldBeFalse)
+				}
+			})
+
+			Convey("Should return org user not found for a user outside the org", func() {
+				ok, err := g.CanApproveAccessRequest(otherUserID+1, m.PERMISSION_VIEW)
+				So(err, ShouldEqual, m.ErrOrgUserNotFound)
+				So(ok, ShouldBeFalse)
+			})
+		})
+
+		Convey("Given user is org admin", func() {
+			g := newTestGuardian(m.ROLE_ADMIN, map[int64][]*m.DashboardAclInfoDTO{})
+			setupOrgMembers()
+
+			Convey("Should be allowed to approve an admin request", func() {
+				ok, err := g.CanApproveAccessRequest(otherUserID, m.PERMISSION_ADMIN)
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeTrue)
+			})
+
+			Convey("Should be denied to approve an unknown level", func() {
+				for _, level := range []m.PermissionType{0, m.PERMISSION_ADMIN << 1} {
+					ok, err := g.CanApproveAccessRequest(otherUserID, level)
+					So(err, ShouldEqual, ErrGuardianGrantExceedsOwn)
+					So(ok, ShouldBeFalse)
+				}
+			})
+		})
+
+		Convey("Given user has edit permission", func() {
+			g := newTestGuardian(m.ROLE_VIEWER, map[int64][]*m.DashboardAclInfoDTO{
+				dashboardID: {toDto(newDefaultUserPermission(dashboardID, m.PERMISSION_EDIT))},
+			})
+			setupOrgMembers()
+
+			Convey("Should not be allowed to approve a view request", func() {
+				ok, err := g.CanApproveAccessRequest(otherUserID, m.PERMISSION_VIEW)
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeFalse)
+			})
+		})
+	})
+}
+
+func TestGuardianCanPinToPersonalHome(t *testing.T) {
+	Convey("Guardian pin to personal home tests", t, func() {
+		Convey("Given user can view the dashboard", func() {
+			g := newTestGuardian(m.ROLE_VIEWER, map[int64][]*m.DashboardAclInfoDTO{
+				dashboardID: {toDto(newDefaultUserPermission(dashboardID, m.PERMISSION_VIEW))},
+			})
+
+			Convey("Should be allowed to pin to personal home", func() {
+				ok, err := g.CanPinToPersonalHome()
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeTrue)
+			})
+		})
+
+		Convey("Given user cannot view the dashboard", func() {
+			g := newTestGuardian(m.ROLE_VIEWER, map[int64][]*m.DashboardAclInfoDTO{})
+
+			Convey("Should not be allowed to pin to personal home", func() {
+				ok, err := g.CanPinToPersonalHome()
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeFalse)
+			})
+		})
+	})
+}
+
+func TestGuardianCanEditLinks(t *testing.T) {
+	Convey("Guardian edit links tests", t, func() {
+		Convey("Given user has edit permission", func() {
+			g := newTestGuardian(m.ROLE_VIEWER, map[int64][]*m.DashboardAclInfoDTO{
+				dashboardID: {toDto(newDefaultUserPermission(dashboardID, m.PERMISSION_EDIT))},
+			})
+
+			Convey("Should be allowed to edit links", func() {
+				ok, err := g.CanEditLinks()
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeTrue)
+			})
+		})
+
+		Convey("Given user has view permission", func() {
+			g := newTestGuardian(m.ROLE_VIEWER, map[int64][]*m.DashboardAclInfoDTO{
+				dashboardID: {toDto(newDefaultUserPermission(dashboardID, m.PERMISSION_VIEW))},
+			})
+
+			Convey("Should not be allowed to edit links", func() {
+				ok, err := g.CanEditLinks()
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeFalse)
+			})
+		})
+	})
+}
+
+func TestGuardianCanExportCSV(t *testing.T) {
+	Convey("Guardian export CSV tests", t, func() {
+		acl := map[int64][]*m.DashboardAclInfoDTO{
+			dashboardID: {toDto(newViewerRolePermission(dashboardID, m.PERMISSION_VIEW))},
+		}
+
+		Convey("Given viewer can query all data sources", func() {
+			g := newTestGuardian(m.ROLE_VIEWER, acl)
+			setupTestDatasources("graphite", "prometheus")
+
+			Convey("Should be allowed to export CSV", func() {
+				ok, err := g.CanExportCSV([]string{"graphite", "prometheus"})
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeTrue)
+			})
+		})
+
+		Convey("Given viewer cannot query one of the data sources", func() {
+			g := newTestGuardian(m.ROLE_VIEWER, acl)
+			setupTestDatasources("graphite")
+
+			Convey("Should be denied with the data source requirement", func() {
+				ok, err := g.CanExportCSV([]string{"graphite", "prometheus"})
+				So(err, ShouldResemble, PermissionDeniedError{DashboardId: dashboardID, Datasource: "prometheus", Requirement: "Query"})
+				So(ok, ShouldBeFalse)
+			})
+		})
+
+		Convey("Given user cannot view the dashboard", func() {
+			g := newTestGuardian(m.ROLE_VIEWER, map[int64][]*m.DashboardAclInfoDTO{})
+			setupTestDatasources("graphite")
+
+			Convey("Should be denied with the view requirement", func() {
+				ok, err := g.CanExportCSV([]string{"graphite"})
+				So(err, ShouldResemble, PermissionDeniedError{DashboardId: dashboardID, Requirement: "View"})
+				So(ok, ShouldBeFalse)
+			})
+		})
+	})
+}
+
+func TestGuardianCanEditCachingPolicy(t *testing.T) {
+	Convey("Guardian edit caching policy tests", t, func() {
+		Convey("Given user has admin permission", func() {
+			g := newTestGuardian(m.ROLE_VIEWER, map[int64][]*m.DashboardAclInfoDTO{
+				dashboardID: {toDto(newDefaultUserPermission(dashboardID, m.PERMISSION_ADMIN))},
+			})
+
+			Convey("Should be allowed to edit caching policy", func() {
+				ok, err := g.CanEditCachingPolicy()
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeTrue)
+			})
+		})
+
+		Convey("Given user has edit permission", func() {
+			g := newTestGuardian(m.ROLE_VIEWER, map[int64][]*m.DashboardAclInfoDTO{
+				dashboardID: {toDto(newDefaultUserPermission(dashboardID, m.PERMISSION_EDIT))},
+			})
+
+			Convey("Should not be allowed to edit caching policy", func() {
+				ok, err := g.CanEditCachingPolicy()
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeFalse)
+			})
+		})
+	})
+}
+
+func TestGuardianCanBulkMoveFolderContents(t *testing.T) {
+	Convey("Guardian bulk move folder contents tests", t, func() {
+		acl := map[int64][]*m.DashboardAclInfoDTO{
+			parentFolderID: {toDto(newDefaultUserPermission(parentFolderID, m.PERMISSION_EDIT))},
+			otherFolderID:  {toDto(newDefaultUserPermission(otherFolderID, m.PERMISSION_EDIT))},
+		}
+
+		Convey("Given user can edit both folders and all dashboards", func() {
+			g := newTestFolderGuardian(m.ROLE_VIEWER, acl)
+			setupTestFolderChildren([]*m.DashboardPermissionForUser{
+				{DashboardId: childDashboardID, Permission: m.PERMISSION_EDIT},
+				{DashboardId: otherDashboardID, Permission: m.PERMISSION_ADMIN},
+			})
+
+			Convey("Should be allowed to move the folder contents", func() {
+				ok, blocked, err := g.CanBulkMoveFolderContents(otherFolderID)
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeTrue)
+				So(blocked, ShouldBeEmpty)
+			})
+		})
+
+		Convey("Given user can only view one dashboard in the folder", func() {
+			g := newTestFolderGuardian(m.ROLE_VIEWER, acl)
+			setupTestFolderChildren([]*m.DashboardPermissionForUser{
+				{DashboardId: childDashboardID, Permission: m.PERMISSION_VIEW},
+				{DashboardId: otherDashboardID, Permission: m.PERMISSION_EDIT},
+			})
+
+			Convey("Should not be allowed and report the blocked dashboard", func() {
+				ok, blocked, err := g.CanBulkMoveFolderContents(otherFolderID)
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeFalse)
+				So(blocked, ShouldResemble, []int64{childDashboardID})
+			})
+		})
+
+		Convey("Given user cannot edit the target folder", func() {
+			g := newTestFolderGuardian(m.ROLE_VIEWER, map[int64][]*m.DashboardAclInfoDTO{
+				parentFolderID: {toDto(newDefaultUserPermission(parentFolderID, m.PERMISSION_EDIT))},
+				otherFolderID:  {toDto(newDefaultUserPermission(otherFolderID, m.PERMISSION_VIEW))},
+			})
+
+			Convey("Should not be allowed to move the folder contents", func() {
+				ok, _, err := g.CanBulkMoveFolderContents(otherFolderID)
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeFalse)
+			})
+		})
+	})
+}
+
+func TestGuardianCanAssignToWorkspace(t *testing.T) {
+	Convey("Guardian assign to workspace tests", t, func() {
+		Convey("Given user is admin of the dashboard and can edit the workspace", func() {
+			g := newTestGuardian(m.ROLE_VIEWER, map[int64][]*m.DashboardAclInfoDTO{
+				dashboardID:    {toDto(newDefaultUserPermission(dashboardID, m.PERMISSION_ADMIN))},
+				parentFolderID: {toDto(newDefaultUserPermission(parentFolderID, m.PERMISSION_EDIT))},
+			})
+
+			Convey("Should be allowed to assign to the workspace", func() {
+				ok, err := g.CanAssignToWorkspace(parentFolderID)
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeTrue)
+			})
+		})
+
+		Convey("Given user cannot edit the workspace", func() {
+			g := newTestGuardian(m.ROLE_VIEWER, map[int64][]*m.DashboardAclInfoDTO{
+				dashboardID:    {toDto(newDefaultUserPermission(dashboardID, m.PERMISSION_ADMIN))},
+				parentFolderID: {toDto(newDefaultUserPermission(parentFolderID, m.PERMISSION_VIEW))},
+			})
+
+			Convey("Should be denied with the workspace requirement", func() {
+				ok, err := g.CanAssignToWorkspace(parentFolderID)
+				So(err, ShouldResemble, PermissionDeniedError{DashboardId: parentFolderID, Requirement: "Edit"})
+				So(ok, ShouldBeFalse)
+			})
+		})
+
+		Convey("Given user is not admin of the dashboard", func() {
+			g := newTestGuardian(m.ROLE_VIEWER, map[int64][]*m.DashboardAclInfoDTO{
+				dashboardID:    {toDto(newDefaultUserPermission(dashboardID, m.PERMISSION_EDIT))},
+				parentFolderID: {toDto(newDefaultUserPermission(parentFolderID, m.PERMISSION_EDIT))},
+			})
+
+			Convey("Should be denied with the dashboard requirement", func() {
+				ok, err := g.CanAssignToWorkspace(parentFolderID)
+				So(err, ShouldResemble, PermissionDeniedError{DashboardId: dashboardID, Requirement: "Admin"})
+				So(ok, ShouldBeFalse)
+			})
+		})
+	})
+}
+
+func TestGuardianCanSeeLockHolder(t *testing.T) {
+	Convey("Guardian see lock holder tests", t, func() {
+		viewerAcl := map[int64][]*m.DashboardAclInfoDTO{
+			dashboardID: {toDto(newDefaultUserPermission(dashboardID, m.PERMISSION_VIEW))},
+		}
+		editorAcl := map[int64][]*m.DashboardAclInfoDTO{
+			dashboardID: {toDto(newDefaultUserPermission(dashboardID, m.PERMISSION_EDIT))},
+		}
+
+		Convey("Given lock holder privacy is disabled", func() {
+			setting.DashboardLockHolderPrivacy = false
+
+			Convey("Should allow user with view permission", func() {
+				ok, err := newTestGuardian(m.ROLE_VIEWER, viewerAcl).CanSeeLockHolder()
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeTrue)
+			})
+
+			Convey("Should not allow user without permission", func() {
+				ok, err := newTestGuardian(m.ROLE_VIEWER, map[int64][]*m.DashboardAclInfoDTO{}).CanSeeLockHolder()
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeFalse)
+			})
+		})
+
+		Convey("Given lock holder privacy is enabled", func() {
+			setting.DashboardLockHolderPrivacy = true
+			Reset(func() { setting.DashboardLockHolderPrivacy = false })
+
+			Convey("Should not allow user with view permission", func() {
+				ok, err := newTestGuardian(m.ROLE_VIEWER, viewerAcl).CanSeeLockHolder()
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeFalse)
+			})
+
+			Convey("Should allow user with edit permission", func() {
+				ok, err := newTestGuardian(m.ROLE_VIEWER, editorAcl).CanSeeLockHolder()
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeTrue)
+			})
+		})
+	})
+}
+
+func TestGuardianCanCleanupSnapshots(t *testing.T) {
+	Convey("Guardian cleanup snapshots tests", t, func() {
+		Convey("Given user has admin permission", func() {
+			g := newTestGuardian(m.ROLE_VIEWER, map[int64][]*m.DashboardAclInfoDTO{
+				dashboardID: {toDto(newDefaultUserPermission(dashboardID, m.PERMISSION_ADMIN))},
+			})
+
+			Convey("Should be allowed to cleanup snapshots", func() {
+				ok, err := g.CanCleanupSnapshots()
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeTrue)
+			})
+		})
+
+		Convey("Given user has edit permission", func() {
+			g := newTestGuardian(m.ROLE_VIEWER, map[int64][]*m.DashboardAclInfoDTO{
+				dashboardID: {toDto(newDefaultUserPermission(dashboardID, m.PERMISSION_EDIT))},
+			})
+
+			Convey("Should not be allowed to cleanup snapshots", func() {
+				ok, err := g.CanCleanupSnapshots()
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeFalse)
+			})
+		})
+	})
+}
+
+func TestGuardianCanApplyTemplateRecursively(t *testing.T) {
+	Convey("Guardian apply template recursively tests", t, func() {
+		Convey("Given user is admin of the folder and all dashboards", func() {
+			g := newTestGuardian(m.ROLE_VIEWER, map[int64][]*m.DashboardAclInfoDTO{
+				parentFolderID: {toDto(newDefaultUserPermission(parentFolderID, m.PERMISSION_ADMIN))},
+			})
+			setupTestFolderChildren([]*m.DashboardPermissionForUser{
+				{DashboardId: childDashboardID, Permission: m.PERMISSION_ADMIN},
+				{DashboardId: otherDashboardID, Permission: m.PERMISSION_ADMIN},
+			})
+
+			Convey("Should be allowed to apply the template", func() {
+				ok, blocked, err := g.CanApplyTemplateRecursively(parentFolderID)
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeTrue)
+				So(blocked, ShouldBeEmpty)
+			})
+		})
+
+		Convey("Given user cannot administer one dashboard in the folder", func() {
+			g := newTestGuardian(m.ROLE_VIEWER, map[int64][]*m.DashboardAclInfoDTO{
+				parentFolderID: {toDto(newDefaultUserPermission(parentFolderID, m.PERMISSION_ADMIN))},
+			})
+			setupTestFolderChildren([]*m.DashboardPermissionForUser{
+				{DashboardId: childDashboardID, Permission: m.PERMISSION_ADMIN},
+				{DashboardId: otherDashboardID, Permission: m.PERMISSION_EDIT},
+			})
+
+			Convey("Should not be allowed and report the blocked dashboard", func() {
+				ok, blocked, err := g.CanApplyTemplateRecursively(parentFolderID)
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeFalse)
+				So(blocked, ShouldResemble, []int64{otherDashboardID})
+			})
+		})
+
+		Convey("Given user cannot administer the folder", func() {
+			g := newTestGuardian(m.ROLE_VIEWER, map[int64][]*m.DashboardAclInfoDTO{
+				parentFolderID: {toDto(newDefaultUserPermission(parentFolderID, m.PERMISSION_EDIT))},
+			})
+			setupTestFolderChildren([]*m.DashboardPermissionForUser{
+				{DashboardId: childDashboardID, Permission: m.PERMISSION_ADMIN},
+				{DashboardId: otherDashboardID, Permission: m.PERMISSION_ADMIN},
+			})
+
+			Convey("Should not be allowed and report the folder", func() {
+				ok, blocked, err := g.CanApplyTemplateRecursively(parentFolderID)
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeFalse)
+				So(blocked, ShouldResemble, []int64{parentFolderID})
+			})
+		})
+	})
+}
+
+func TestGuardianCanViewProvisioningSource(t *testing.T) {
+	Convey("Guardian view provisioning source tests", t, func() {
+		Convey("Given user has admin permission", func() {
+			g := newTestGuardian(m.ROLE_VIEWER, map[int64][]*m.DashboardAclInfoDTO{
+				dashboardID: {toDto(newDefaultUserPermission(dashboardID, m.PERMISSION_ADMIN))},
+			})
+
+			Convey("Should be allowed to view provisioning source", func() {
+				ok, err := g.CanViewProvisioningSource()
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeTrue)
+			})
+		})
+
+		Convey("Given user has edit permission", func() {
+			g := newTestGuardian(m.ROLE_VIEWER, map[int64][]*m.DashboardAclInfoDTO{
+				dashboardID: {toDto(newDefaultUserPermission(dashboardID, m.PERMISSION_EDIT))},
+			})
+
+			Convey("Should not be allowed to view provisioning source", func() {
+				ok, err := g.CanViewProvisioningSource()
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeFalse)
+			})
+		})
+	})
+}
+
+func TestGuardianCanCollapseToInherited(t *testing.T) {
+	Convey("Guardian collapse to inherited tests", t, func() {
+		setupDashboardInFolder := func() {
+			bus.AddHandler("test", func(query *m.GetDashboardQuery) error {
+				query.Result = &m.Dashboard{Id: query.Id, OrgId: query.OrgId, FolderId: parentFolderID}
+				return nil
+			})
+		}
+
+		Convey("Given user is admin of the dashboard and its folder", func() {
+			g := newTestGuardian(m.ROLE_VIEWER, map[int64][]*m.DashboardAclInfoDTO{
+				dashboardID:    {toDto(newDefaultUserPermission(dashboardID, m.PERMISSION_ADMIN))},
+				parentFolderID: {toDto(newDefaultUserPermission(parentFolderID, m.PERMISSION_ADMIN))},
+			})
+			setupDashboardInFolder()
+
+			Convey("Should be allowed to collapse to inherited permissions", func() {
+				ok, err := g.CanCollapseToInherited()
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeTrue)
+			})
+		})
+
+		Convey("Given user is admin of the dashboard but not of its folder", func() {
+			g := newTestGuardian(m.ROLE_VIEWER, map[int64][]*m.DashboardAclInfoDTO{
+				dashboardID:    {toDto(newDefaultUserPermission(dashboardID, m.PERMISSION_ADMIN))},
+				parentFolderID: {toDto(newDefaultUserPermission(parentFolderID, m.PERMISSION_VIEW))},
+			})
+			setupDashboardInFolder()
+
+			Convey("Should be denied as it would remove the last admin", func() {
+				ok, err := g.CanCollapseToInherited()
+				So(err, ShouldEqual, ErrWouldRemoveLastAdmin)
+				So(ok, ShouldBeFalse)
+			})
+		})
+
+		Convey("Given user is not admin of the dashboard", func() {
+			g := newTestGuardian(m.ROLE_VIEWER, map[int64][]*m.DashboardAclInfoDTO{
+				dashboardID: {toDto(newDefaultUserPermission(dashboardID, m.PERMISSION_EDIT))},
+			})
+
+			Convey("Should not be allowed to collapse to inherited permissions", func() {
+				ok, err := g.CanCollapseToInherited()
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeFalse)
+			})
+		})
+	})
+}
+
+func TestGuardianCanShareWithOrg(t *testing.T) {
+	Convey("Guardian share with org tests", t, func() {
+		Convey("Given user is server admin", func() {
+			newTestGuardian(m.ROLE_ADMIN, map[int64][]*m.DashboardAclInfoDTO{})
+			g := New(dashboardID, orgID, &m.SignedInUser{UserId: userID, OrgId: orgID, OrgRole: m.ROLE_ADMIN, IsGrafanaAdmin: true})
+
+			Convey("Should be allowed to share with another org", func() {
+				ok, err := g.CanShareWithOrg(orgID + 1)
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeTrue)
+			})
+		})
+
+		Convey("Given user is org admin", func() {
+			g := newTestGuardian(m.ROLE_ADMIN, map[int64][]*m.DashboardAclInfoDTO{})
+
+			Convey("Should be denied with the server admin requirement", func() {
+				ok, err := g.CanShareWithOrg(orgID + 1)
+				So(err, ShouldResemble, PermissionDeniedError{DashboardId: dashboardID, Requirement: "server admin"})
+				So(ok, ShouldBeFalse)
+			})
+		})
+
+		Convey("Given user is not admin of the dashboard", func() {
+			g := newTestGuardian(m.ROLE_EDITOR, map[int64][]*m.DashboardAclInfoDTO{
+				dashboardID: {toDto(newDefaultUserPermission(dashboardID, m.PERMISSION_EDIT))},
+			})
+
+			Convey("Should be denied with the admin requirement", func() {
+				ok, err := g.CanShareWithOrg(orgID + 1)
+				So(err, ShouldResemble, PermissionDeniedError{DashboardId: dashboardID, Requirement: "Admin"})
+				So(ok, ShouldBeFalse)
+			})
+		})
+	})
+}
+
+func TestGuardianCanPromotePanelToWidget(t *testing.T) {
+	Convey("Guardian promote panel to widget tests", t, func() {
+		Convey("Given user is org admin", func() {
+			g := newTestGuardian(m.ROLE_ADMIN, map[int64][]*m.DashboardAclInfoDTO{})
+
+			Convey("Should be allowed to promote a panel", func() {
+				ok, err := g.CanPromotePanelToWidget(1)
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeTrue)
+			})
+		})
+
+		Convey("Given editor is admin of the dashboard", func() {
+			g := newTestGuardian(m.ROLE_EDITOR, map[int64][]*m.DashboardAclInfoDTO{
+				dashboardID: {toDto(newDefaultUserPermission(dashboardID, m.PERMISSION_ADMIN))},
+			})
+
+			Convey("Should be denied with the org admin requirement", func() {
+				ok, err := g.CanPromotePanelToWidget(1)
+				So(err, ShouldResemble, PermissionDeniedError{DashboardId: dashboardID, Requirement: "org admin"})
+				So(ok, ShouldBeFalse)
+			})
+		})
+
+		Convey("Given editor can edit the dashboard", func() {
+			g := newTestGuardian(m.ROLE_EDITOR, map[int64][]*m.DashboardAclInfoDTO{
+				dashboardID: {toDto(newDefaultUserPermission(dashboardID, m.PERMISSION_EDIT))},
+			})
+
+			Convey("Should be denied with the dashboard admin requirement", func() {
+				ok, err := g.CanPromotePanelToWidget(1)
+				So(err, ShouldResemble, PermissionDeniedError{DashboardId: dashboardID, Requirement: "Admin"})
+				So(ok, ShouldBeFalse)
+			})
+		})
+	})
+}
+
+func TestGetPermissionsForUser(t *testing.T) {
+	Convey("Get permissions for user tests", t, func() {
+		Convey("Given a folder only the owner has access to", func() {
+			f := setupTestRestrictedFolder(t)
+
+			Convey("Should not give the viewer the permissions of the owner", func() {
+				permissions, err := getPermissionsForUser(orgID, f.viewer, f.dashboardIDs())
+				So(err, ShouldBeNil)
+				So(permissions, ShouldResemble, map[int64]m.PermissionType{f.sharedID: m.PERMISSION_ADMIN, f.openID: m.PERMISSION_VIEW})
+			})
+
+			Convey("Should resolve the folder acl for the owner", func() {
+				permissions, err := getPermissionsForUser(orgID, f.owner, f.dashboardIDs())
+				So(err, ShouldBeNil)
+				So(permissions, ShouldResemble, map[int64]m.PermissionType{f.childID: m.PERMISSION_ADMIN, f.sharedID: m.PERMISSION_ADMIN, f.openID: m.PERMISSION_EDIT})
+			})
+
+			Convey("Should match the permissions of a guardian per dashboard", func() {
+				for _, user := range []*m.SignedInUser{f.viewer, f.owner} {
+					permissions, err := getPermissionsForUser(orgID, user, f.dashboardIDs())
+					So(err, ShouldBeNil)
+
+					for _, id := range f.dashboardIDs() {
+						for _, permission := range []m.PermissionType{m.PERMISSION_VIEW, m.PERMISSION_EDIT, m.PERMISSION_ADMIN} {
+							ok, err := New(id, orgID, user).HasPermission(permission)
+							So(err, ShouldBeNil)
+							So(permissions[id] >= permission, ShouldEqual, ok)
+						}
+					}
+				}
+			})
+		})
+	})
+}
+
+func TestBatchCanDeleteSnapshots(t *testing.T) {
+	Convey("Batch can delete snapshots tests", t, func() {
+		bus.ClearBusHandlers()
+
+		queries := setupTestBatchAcl(userPermissionsAcl([]*m.DashboardPermissionForUser{
+			{DashboardId: dashboardID, Permission: m.PERMISSION_ADMIN},
+			{DashboardId: childDashboardID, Permission: m.PERMISSION_EDIT},
+		}))
+
+		Convey("Given user has mixed admin rights", func() {
+			user := &m.SignedInUser{UserId: userID, OrgId: orgID, OrgRole: m.ROLE_EDITOR}
+
+			Convey("Should only allow dashboards the user is admin of", func() {
+				result, err := BatchCanDeleteSnapshots(orgID, []int64{dashboardID, childDashboardID, otherDashboardID}, user)
+				So(err, ShouldBeNil)
+				So(result, ShouldResemble, map[int64]bool{dashboardID: true, childDashboardID: false, otherDashboardID: false})
+				So(*queries, ShouldEqual, 1)
+			})
+		})
+
+		Convey("Given a folder only the owner has access to", func() {
+			f := setupTestRestrictedFolder(t)
+
+			Convey("Should only allow the viewer the dashboard shared with them", func() {
+				result, err := BatchCanDeleteSnapshots(orgID, f.dashboardIDs(), f.viewer)
+				So(err, ShouldBeNil)
+				So(result, ShouldResemble, map[int64]bool{f.childID: false, f.sharedID: true, f.openID: false})
+			})
+
+			Convey("Should allow the owner the dashboards of the folder", func() {
+				result, err := BatchCanDeleteSnapshots(orgID, f.dashboardIDs(), f.owner)
+				So(err, ShouldBeNil)
+				So(result, ShouldResemble, map[int64]bool{f.childID: true, f.sharedID: true, f.openID: false})
+			})
+		})
+	})
+}
+
+func TestBatchCanRestore(t *testing.T) {
+	Convey("Batch can restore tests", t, func() {
+		setupTrash := func(role m.RoleType) *m.SignedInUser {
+			newTestGuardian(role, map[int64][]*m.DashboardAclInfoDTO{
+				0: {toDto(newEditorRolePermission(defaultDashboardID, m.PERMISSION_EDIT))},
+			})
+
+			bus.AddHandler("test", func(query *m.GetDashboardsQuery) error {
+				query.Result = []*m.Dashboard{
+					{Id: dashboardID, OrgId: orgID, FolderId: parentFolderID},
+					{Id: childDashboardID, OrgId: orgID, FolderId: otherFolderID},
+					{Id: otherDashboardID, OrgId: orgID, FolderId: 0},
+				}
+				return nil
+			})
+
+			setupTestBatchAcl(userPermissionsAcl([]*m.DashboardPermissionForUser{
+				{DashboardId: parentFolderID, Permission: m.PERMISSION_EDIT},
+				{DashboardId: otherFolderID, Permission: m.PERMISSION_VIEW},
+			}))
+
+			return &m.SignedInUser{UserId: userID, OrgId: orgID, OrgRole: role}
+		}
+
+		ids := []int64{dashboardID, childDashboardID, otherDashboardID}
+
+		Convey("Given editor with mixed folder permissions", func() {
+			user := setupTrash(m.ROLE_EDITOR)
+
+			Convey("Should only allow dashboards in folders the user can save in", func() {
+				result, err := BatchCanRestore(orgID, ids, user)
+				So(err, ShouldBeNil)
+				So(result, ShouldResemble, map[int64]bool{dashboardID: true, childDashboardID: false, otherDashboardID: true})
+			})
+
+			Convey("Should not allow dashboards of another org", func() {
+				result, err := BatchCanRestore(orgID+1, ids, user)
+				So(err, ShouldBeNil)
+				So(result, ShouldResemble, map[int64]bool{dashboardID: false, childDashboardID: false, otherDashboardID: false})
+			})
+		})
+
+		Convey("Given viewer with mixed folder permissions", func() {
+			user := setupTrash(m.ROLE_VIEWER)
+
+			Convey("Should not allow dashboards in the General folder", func() {
+				result, err := BatchCanRestore(orgID, ids, user)
+				So(err, ShouldBeNil)
+				So(result, ShouldResemble, map[int64]bool{dashboardID: true, childDashboardID: false, otherDashboardID: false})
+			})
+		})
+
+		Convey("Given a folder only the owner has access to", func() {
+			f := setupTestRestrictedFolder(t)
+
+			Convey("Should not allow restoring into the folder for a user with access to a single dashboard in it", func() {
+				result, err := BatchCanRestore(orgID, f.dashboardIDs(), f.viewer)
+				So(err, ShouldBeNil)
+				So(result, ShouldResemble, map[int64]bool{f.childID: false, f.sharedID: false, f.openID: false})
+			})
+
+			Convey("Should allow the owner to restore into the folder", func() {
+				result, err := BatchCanRestore(orgID, f.dashboardIDs(), f.owner)
+				So(err, ShouldBeNil)
+				So(result, ShouldResemble, map[int64]bool{f.childID: true, f.sharedID: true, f.openID: true})
+			})
+		})
+	})
+}
+
+func TestGetFolderChildrenAcl(t *testing.T) {
+	Convey("Get folder children acl tests", t, func() {
+		bus.ClearBusHandlers()
+		setupTestFolderChildren(nil)
+
+		var queries int
+		bus.AddHandler("test", func(query *m.GetDashboardsAclInfoListQuery) error {
+			queries++
+			query.Result = map[int64][]*m.DashboardAclInfoDTO{}
+			for _, id := range query.DashboardIds {
+				query.Result[id] = []*m.DashboardAclInfoDTO{toDto(newDefaultUserPermission(id, m.PERMISSION_EDIT))}
+			}
+			return nil
+		})
+
+		Convey("Should load the acl of all children in a single query", func() {
+			result, err := GetFolderChildrenAcl(orgID, parentFolderID)
+			So(err, ShouldBeNil)
+			So(len(result), ShouldEqual, 2)
+			So(result[childDashboardID][0].DashboardId, ShouldEqual, childDashboardID)
+			So(result[otherDashboardID][0].DashboardId, ShouldEqual, otherDashboardID)
+			So(queries, ShouldEqual, 1)
+		})
+	})
+}
+
+func TestCanBulkTagByFilter(t *testing.T) {
+	Convey("Can bulk tag by filter tests", t, func() {
+		bus.ClearBusHandlers()
+
+		var searched search.Query
+		bus.AddHandler("test", func(query *search.Query) error {
+			searched = *query
+			query.Result = search.HitList{
+				{Id: dashboardID, Type: search.DashHitDB},
+				{Id: childDashboardID, Type: search.DashHitDB},
+				{Id: otherDashboardID, Type: search.DashHitDB},
+			}
+			return nil
+		})
+
+		setupTestBatchAcl(userPermissionsAcl([]*m.DashboardPermissionForUser{
+			{DashboardId: dashboardID, Permission: m.PERMISSION_EDIT},
+			{DashboardId: childDashboardID, Permission: m.PERMISSION_VIEW},
+			{DashboardId: otherDashboardID, Permission: m.PERMISSION_ADMIN},
+		}))
+
+		Convey("Given the filter matches editable and view only dashboards", func() {
+			user := &m.SignedInUser{UserId: userID, OrgId: orgID, OrgRole: m.ROLE_VIEWER}
+
+			Convey("Should split the matches by edit permission", func() {
+				editable, blocked, err := CanBulkTagByFilter(search.Query{Tags: []string{"prod"}}, user)
+				So(err, ShouldBeNil)
+				So(editable, ShouldResemble, []int64{dashboardID, otherDashboardID})
+				So(blocked, ShouldResemble, []int64{childDashboardID})
+			})
+
+			Convey("Should only search the dashboards the user can see in their org", func() {
+				_, _, err := CanBulkTagByFilter(search.Query{Tags: []string{"prod"}, OrgId: orgID + 1}, user)
+				So(err, ShouldBeNil)
+				So(searched.Tags, ShouldResemble, []string{"prod"})
+				So(searched.OrgId, ShouldEqual, orgID)
+				So(searched.Type, ShouldEqual, string(search.DashHitDB))
+				So(searched.Permission, ShouldEqual, m.PERMISSION_VIEW)
+			})
+		})
+
+		Convey("Given the filter matches a dashboard shared out of a folder only the owner has access to", func() {
+			f := setupTestRestrictedFolder(t)
+			bus.AddHandler("test", func(query *search.Query) error {
+				query.Result = search.HitList{
+					{Id: f.sharedID, Type: search.DashHitDB},
+					{Id: f.openID, Type: search.DashHitDB},
+				}
+				return nil
+			})
+
+			Convey("Should resolve edit from the acl of the shared dashboard", func() {
+				editable, blocked, err := CanBulkTagByFilter(search.Query{Tags: []string{"prod"}}, f.viewer)
+				So(err, ShouldBeNil)
+				So(editable, ShouldResemble, []int64{f.sharedID})
+				So(blocked, ShouldResemble, []int64{f.openID})
+			})
+		})
+	})
+}
+
+func TestCanBulkMoveSearchResults(t *testing.T) {
+	Convey("Can bulk move search results tests", t, func() {
+		newTestGuardian(m.ROLE_VIEWER, map[int64][]*m.DashboardAclInfoDTO{
+			parentFolderID: {toDto(newDefaultUserPermission(parentFolderID, m.PERMISSION_EDIT))},
+			otherFolderID:  {toDto(newDefaultUserPermission(otherFolderID, m.PERMISSION_VIEW))},
+		})
+
+		queries := setupTestBatchAcl(userPermissionsAcl([]*m.DashboardPermissionForUser{
+			{DashboardId: dashboardID, Permission: m.PERMISSION_EDIT},
+			{DashboardId: childDashboardID, Permission: m.PERMISSION_VIEW},
+			{DashboardId: otherDashboardID, Permission: m.PERMISSION_ADMIN},
+		}))
+
+		user := &m.SignedInUser{UserId: userID, OrgId: orgID, OrgRole: m.ROLE_VIEWER}
+		ids := []int64{dashboardID, childDashboardID, otherDashboardID}
+
+		Convey("Given user can edit the target folder", func() {
+			Convey("Should block the results the user can not edit", func() {
+				movable, blocked, err := CanBulkMoveSearchResults(ids, parentFolderID, user)
+				So(err, ShouldBeNil)
+				So(movable, ShouldResemble, []int64{dashboardID, otherDashboardID})
+				So(blocked, ShouldResemble, []int64{childDashboardID})
+				So(*queries, ShouldEqual, 1)
+			})
+		})
+
+		Convey("Given user can only view the target folder", func() {
+			Convey("Should block all results", func() {
+				movable, blocked, err := CanBulkMoveSearchResults(ids, otherFolderID, user)
+				So(err, ShouldBeNil)
+				So(movable, ShouldBeEmpty)
+				So(blocked, ShouldResemble, ids)
+			})
+		})
+
+		Convey("Given results in a folder only the owner has access to", func() {
+			f := setupTestRestrictedFolder(t)
+			targetID := insertTestDashboard("Target", 0, true)
+			updateTestAcl(targetID, &m.DashboardAcl{UserId: f.viewer.UserId, Permission: m.PERMISSION_EDIT})
+
+			Convey("Should only allow moving the dashboard shared with the user", func() {
+				movable, blocked, err := CanBulkMoveSearchResults(f.dashboardIDs(), targetID, f.viewer)
+				So(err, ShouldBeNil)
+				So(movable, ShouldResemble, []int64{f.sharedID})
+				So(blocked, ShouldResemble, []int64{f.childID, f.openID})
+			})
+
+			Convey("Should block all results when moving into the restricted folder", func() {
+				movable, blocked, err := CanBulkMoveSearchResults(f.dashboardIDs(), f.folderID, f.viewer)
+				So(err, ShouldBeNil)
+				So(movable, ShouldBeEmpty)
+				So(blocked, ShouldResemble, f.dashboardIDs())
+			})
+		})
+	})
+}
+
+func TestGuardianCanRestoreFolder(t *testing.T) {
+	Convey("Guardian restore folder tests", t, func() {
+		acl := map[int64][]*m.DashboardAclInfoDTO{
+			parentFolderID: {toDto(newDefaultUserPermission(parentFolderID, m.PERMISSION_EDIT))},
+		}
+
+		Convey("Given user can restore the folder and all dashboards", func() {
+			g := newTestFolderGuardian(m.ROLE_VIEWER, acl)
+			setupTestFolderChildren([]*m.DashboardPermissionForUser{
+				{DashboardId: childDashboardID, Permission: m.PERMISSION_EDIT},
+				{DashboardId: otherDashboardID, Permission: m.PERMISSION_EDIT},
+			})
+
+			Convey("Should be allowed to restore the folder", func() {
+				ok, blocked, err := g.CanRestoreFolder()
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeTrue)
+				So(blocked, ShouldBeEmpty)
+			})
+		})
+
+		Convey("Given user can only view one dashboard in the trashed folder", func() {
+			g := newTestFolderGuardian(m.ROLE_VIEWER, acl)
+			setupTestFolderChildren([]*m.DashboardPermissionForUser{
+				{DashboardId: childDashboardID, Permission: m.PERMISSION_EDIT},
+				{DashboardId: otherDashboardID, Permission: m.PERMISSION_VIEW},
+			})
+
+			Convey("Should not be allowed and report the blocked dashboard", func() {
+				ok, blocked, err := g.CanRestoreFolder()
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeFalse)
+				So(blocked, ShouldResemble, []int64{otherDashboardID})
+			})
+		})
+
+		Convey("Given user can only view the folder", func() {
+			g := newTestFolderGuardian(m.ROLE_VIEWER, map[int64][]*m.DashboardAclInfoDTO{
+				parentFolderID: {toDto(newDefaultUserPermission(parentFolderID, m.PERMISSION_VIEW))},
+			})
+
+			Convey("Should not be allowed to restore the folder", func() {
+				ok, _, err := g.CanRestoreFolder()
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeFalse)
+			})
+		})
+	})
+}
+
+func TestGuardianAdminOnlyChecks(t *testing.T) {
+	checks := []struct {
+		name  string
+		check func(DashboardGuardian) (bool, error)
+	}{
+		{"CanChangeUID", DashboardGuardian.CanChangeUID},
+		{"CanEditRetentionPolicy", DashboardGuardian.CanEditRetentionPolicy},
+		{"CanEditBranding", DashboardGuardian.CanEditBranding},
+		{"CanEditDataMasking", DashboardGuardian.CanEditDataMasking},
+		{"CanEditQueryTimeout", DashboardGuardian.CanEditQueryTimeout},
+		{"CanEditContactPointOverrides", DashboardGuardian.CanEditContactPointOverrides},
+		{"CanEditFeatureFlags", DashboardGuardian.CanEditFeatureFlags},
+		{"CanEditPIIRedaction", DashboardGuardian.CanEditPIIRedaction},
+		{"CanEditEmbedAllowlist", DashboardGuardian.CanEditEmbedAllowlist},
+		{"CanEditDatasourceFailover", DashboardGuardian.CanEditDatasourceFailover},
+		{"CanExemptFromPolicy", func(g DashboardGuardian) (bool, error) { return g.CanExemptFromPolicy("mandatory-tags") }},
+		{"CanEditRLS", DashboardGuardian.CanEditRLS},
+		{"CanConfigureWebhooks", DashboardGuardian.CanConfigureWebhooks},
+		{"CanEnableAnonymousAccess", DashboardGuardian.CanEnableAnonymousAccess},
+		{"CanConfigureAuditLogging", DashboardGuardian.CanConfigureAuditLogging},
+		{"CanEditExportWatermark", DashboardGuardian.CanEditExportWatermark},
+		{"CanOverrideInheritanceTemporarily", DashboardGuardian.CanOverrideInheritanceTemporarily},
+	}
+
+	Convey("Guardian admin only check tests", t, func() {
+		for _, c := range checks {
+			c := c
+
+			Convey("Given user has admin permission, "+c.name, func() {
+				g := newTestGuardian(m.ROLE_VIEWER, map[int64][]*m.DashboardAclInfoDTO{
+					dashboardID: {toDto(newDefaultUserPermission(dashboardID, m.PERMISSION_ADMIN))},
+				})
+
+				Convey("Should be allowed", func() {
+					ok, err := c.check(g)
+					So(err, ShouldBeNil)
+					So(ok, ShouldBeTrue)
+				})
+			})
+
+			Convey("Given user has edit permission, "+c.name, func() {
+				g := newTestGuardian(m.ROLE_VIEWER, map[int64][]*m.DashboardAclInfoDTO{
+					dashboardID: {toDto(newDefaultUserPermission(dashboardID, m.PERMISSION_EDIT))},
+				})
+
+				Convey("Should be denied with the admin requirement", func() {
+					ok, err := c.check(g)
+					So(err, ShouldResemble, PermissionDeniedError{DashboardId: dashboardID, Requirement: "Admin"})
+					So(ok, ShouldBeFalse)
+				})
+			})
+		}
+	})
+}
+
+func TestGuardianEditOnlyChecks(t *testing.T) {
+	checks := []struct {
+		name  string
+		check func(DashboardGuardian) (bool, error)
+	}{
+		{"CanConfigureSLO", DashboardGuardian.CanConfigureSLO},
+		{"CanManageSilences", DashboardGuardian.CanManageSilences},
+		{"CanDeprecate", DashboardGuardian.CanDeprecate},
+		{"CanEditDefaultVariables", DashboardGuardian.CanEditDefaultVariables},
+	}
+
+	Convey("Guardian edit only check tests", t, func() {
+		for _, c := range checks {
+			c := c
+
+			Convey("Given user has edit permission, "+c.name, func() {
+				g := newTestGuardian(m.ROLE_VIEWER, map[int64][]*m.DashboardAclInfoDTO{
+					dashboardID: {toDto(newDefaultUserPermission(dashboardID, m.PERMISSION_EDIT))},
+				})
+
+				Convey("Should be allowed", func() {
+					ok, err := c.check(g)
+					So(err, ShouldBeNil)
+					So(ok, ShouldBeTrue)
+				})
+			})
+
+			Convey("Given user has view permission, "+c.name, func() {
+				g := newTestGuardian(m.ROLE_VIEWER, map[int64][]*m.DashboardAclInfoDTO{
+					dashboardID: {toDto(newDefaultUserPermission(dashboardID, m.PERMISSION_VIEW))},
+				})
+
+				Convey("Should be denied with the edit requirement", func() {
+					ok, err := c.check(g)
+					So(err, ShouldResemble, PermissionDeniedError{DashboardId: dashboardID, Requirement: "Edit"})
+					So(ok, ShouldBeFalse)
+				})
+			})
+		}
+	})
+}
+
+func TestGuardianCanBulkChangeOwner(t *testing.T) {
+	Convey("Guardian bulk change owner tests", t, func() {
+		setupPermissions := func(userPermissions, ownerPermissions []*m.DashboardPermissionForUser) {
+			setupTestFolderChildren(nil)
+
+			bus.AddHandler("test", func(query *m.GetSignedInUserQuery) error {
+				query.Result = &m.SignedInUser{UserId: query.UserId, OrgId: query.OrgId, OrgRole: m.ROLE_VIEWER}
+				if query.UserId != userID && query.UserId != otherUserID {
+					query.Result.OrgId = -1
+					query.Result.OrgRole = ""
+				}
+				return nil
+			})
+
+			acl := userPermissionsAcl(userPermissions)
+			for _, p := range ownerPermissions {
+				acl[p.DashboardId] = append(acl[p.DashboardId], toDto(newCustomUserPermission(p.DashboardId, otherUserID, p.Permission)))
+			}
+			setupTestBatchAcl(acl)
+		}
+
+		Convey("Given user is admin of all dashboards and the new owner can view them", func() {
+			g := newTestFolderGuardian(m.ROLE_EDITOR, map[int64][]*m.DashboardAclInfoDTO{})
+			setupPermissions([]*m.DashboardPermissionForUser{
+				{DashboardId: childDashboardID, Permission: m.PERMISSION_ADMIN},
+				{DashboardId: otherDashboardID, Permission: m.PERMISSION_ADMIN},
+			}, []*m.DashboardPermissionForUser{
+				{DashboardId: childDashboardID, Permission: m.PERMISSION_VIEW},
+				{DashboardId: otherDashboardID, Permission: m.PERMISSION_EDIT},
+			})
+
+			Convey("Should be allowed to change the owner", func() {
+				ok, blocked, err := g.CanBulkChangeOwner(otherUserID)
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeTrue)
+				So(blocked, ShouldBeEmpty)
+			})
+		})
+
+		Convey("Given the folder is empty", func() {
+			g := newTestFolderGuardian(m.ROLE_EDITOR, map[int64][]*m.DashboardAclInfoDTO{})
+			setupPermissions(nil, nil)
+			bus.AddHandler("test", func(query *m.GetDashboardsByFolderIdQuery) error {
+				query.Result = []*m.Dashboard{}
+				return nil
+			})
+
+			Convey("Should return org user not found for a new owner outside the org", func() {
+				ok, blocked, err := g.CanBulkChangeOwner(otherUserID + 1)
+				So(err, ShouldEqual, m.ErrOrgUserNotFound)
+				So(ok, ShouldBeFalse)
+				So(blocked, ShouldBeNil)
+			})
+		})
+
+		Convey("Given user cannot administer one dashboard", func() {
+			g := newTestFolderGuardian(m.ROLE_EDITOR, map[int64][]*m.DashboardAclInfoDTO{})
+			setupPermissions([]*m.DashboardPermissionForUser{
+				{DashboardId: childDashboardID, Permission: m.PERMISSION_EDIT},
+				{DashboardId: otherDashboardID, Permission: m.PERMISSION_ADMIN},
+			}, []*m.DashboardPermissionForUser{
+				{DashboardId: childDashboardID, Permission: m.PERMISSION_VIEW},
+				{DashboardId: otherDashboardID, Permission: m.PERMISSION_VIEW},
+			})
+
+			Convey("Should not be allowed and report the blocked dashboard", func() {
+				ok, blocked, err := g.CanBulkChangeOwner(otherUserID)
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeFalse)
+				So(blocked, ShouldResemble, []int64{childDashboardID})
+			})
+		})
+
+		Convey("Given new owner cannot view one dashboard", func() {
+			g := newTestFolderGuardian(m.ROLE_EDITOR, map[int64][]*m.DashboardAclInfoDTO{})
+			setupPermissions([]*m.DashboardPermissionForUser{
+				{DashboardId: childDashboardID, Permission: m.PERMISSION_ADMIN},
+				{DashboardId: otherDashboardID, Permission: m.PERMISSION_ADMIN},
+			}, []*m.DashboardPermissionForUser{
+				{DashboardId: childDashboardID, Permission: m.PERMISSION_VIEW},
+			})
+
+			Convey("Should not be allowed and report the blocked dashboard", func() {
+				ok, blocked, err := g.CanBulkChangeOwner(otherUserID)
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeFalse)
+				So(blocked, ShouldResemble, []int64{otherDashboardID})
+			})
+		})
+
+		Convey("Given a folder only the owner has access to", func() {
+			f := setupTestRestrictedFolder(t)
+			bus.AddHandler("test", func(query *m.GetSignedInUserQuery) error {
+				query.Result = &m.SignedInUser{UserId: query.UserId, OrgId: query.OrgId, OrgRole: m.ROLE_VIEWER}
+				return nil
+			})
+
+			Convey("Should block the dashboards the new owner cannot view", func() {
+				ok, blocked, err := New(f.folderID, orgID, f.owner).CanBulkChangeOwner(f.viewer.UserId)
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeFalse)
+				So(blocked, ShouldResemble, []int64{f.childID})
+			})
+
+			Convey("Should block all dashboards for a new owner without any access", func() {
+				ok, blocked, err := New(f.folderID, orgID, f.owner).CanBulkChangeOwner(otherUserID + 1)
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeFalse)
+				So(blocked, ShouldResemble, []int64{f.childID, f.sharedID})
+			})
+		})
+	})
+}
+
+func TestGuardianCanSetAsOrgLanding(t *testing.T) {
+	Convey("Guardian set as org landing tests", t, func() {
+		Convey("Given user is org admin", func() {
+			g := newTestGuardian(m.ROLE_ADMIN, map[int64][]*m.DashboardAclInfoDTO{})
+
+			Convey("Should be allowed to set the org landing dashboard", func() {
+				ok, err := g.CanSetAsOrgLanding()
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeTrue)
+			})
+		})
+
+		Convey("Given editor is admin of the dashboard", func() {
+			g := newTestGuardian(m.ROLE_EDITOR, map[int64][]*m.DashboardAclInfoDTO{
+				dashboardID: {toDto(newDefaultUserPermission(dashboardID, m.PERMISSION_ADMIN))},
+			})
+
+			Convey("Should not be allowed to set the org landing dashboard", func() {
+				ok, err := g.CanSetAsOrgLanding()
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeFalse)
+			})
+		})
+	})
+}
+
+func TestGuardianCanBulkExportFolder(t *testing.T) {
+	Convey("Guardian bulk export folder tests", t, func() {
+		acl := map[int64][]*m.DashboardAclInfoDTO{
+			parentFolderID: {toDto(newDefaultUserPermission(parentFolderID, m.PERMISSION_VIEW))},
+		}
+
+		Convey("Given user can edit all dashboards in the folder", func() {
+			g := newTestFolderGuardian(m.ROLE_VIEWER, acl)
+			setupTestFolderChildren([]*m.DashboardPermissionForUser{
+				{DashboardId: childDashboardID, Permission: m.PERMISSION_EDIT},
+				{DashboardId: otherDashboardID, Permission: m.PERMISSION_ADMIN},
+			})
+
+			Convey("Should be allowed to export the folder", func() {
+				ok, blocked, err := g.CanBulkExportFolder()
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeTrue)
+				So(blocked, ShouldBeEmpty)
+			})
+		})
+
+		Convey("Given user can only view one dashboard in the folder", func() {
+			g := newTestFolderGuardian(m.ROLE_VIEWER, acl)
+			setupTestFolderChildren([]*m.DashboardPermissionForUser{
+				{DashboardId: childDashboardID, Permission: m.PERMISSION_VIEW},
+				{DashboardId: otherDashboardID, Permission: m.PERMISSION_EDIT},
+			})
+
+			Convey("Should not be allowed and report the blocked dashboard", func() {
+				ok, blocked, err := g.CanBulkExportFolder()
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeFalse)
+				So(blocked, ShouldResemble, []int64{childDashboardID})
+			})
+		})
+
+		Convey("Given a folder only the owner has access to", func() {
+			f := setupTestRestrictedFolder(t)
+
+			Convey("Should not be allowed for a user who cannot view the folder", func() {
+				ok, blocked, err := New(f.folderID, orgID, f.viewer).CanBulkExportFolder()
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeFalse)
+				So(blocked, ShouldBeNil)
+			})
+
+			Convey("Should block the children that only inherit view from the folder", func() {
+				updateTestAcl(f.folderID,
+					&m.DashboardAcl{UserId: f.owner.UserId, Permission: m.PERMISSION_ADMIN},
+					&m.DashboardAcl{UserId: f.viewer.UserId, Permission: m.PERMISSION_VIEW},
+				)
+
+				ok, blocked, err := New(f.folderID, orgID, f.viewer).CanBulkExportFolder()
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeFalse)
+				So(blocked, ShouldResemble, []int64{f.childID})
+			})
+		})
+	})
+}
+
+func TestGuardianCanDetachFromProvisioning(t *testing.T) {
+	Convey("Guardian detach from provisioning tests", t, func() {
+		setupProvisioning := func(provisioned bool) {
+			bus.AddHandler("test", func(query *m.GetProvisionedDashboardDataByIdQuery) error {
+				if provisioned {
+					query.Result = &m.DashboardProvisioning{DashboardId: query.DashboardId, Name: "default"}
+				}
+				return nil
+			})
+		}
+
+		adminAcl := map[int64][]*m.DashboardAclInfoDTO{
+			dashboardID: {toDto(newDefaultUserPermission(dashboardID, m.PERMISSION_ADMIN))},
+		}
+
+		Convey("Given admin of a provisioned dashboard", func() {
+			g := newTestGuardian(m.ROLE_VIEWER, adminAcl)
+			setupProvisioning(true)
+
+			Convey("Should be allowed to detach from provisioning", func() {
+				ok, err := g.CanDetachFromProvisioning()
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeTrue)
+			})
+		})
+
+		Convey("Given admin of a dashboard that is not provisioned", func() {
+			g := newTestGuardian(m.ROLE_VIEWER, adminAcl)
+			setupProvisioning(false)
+
+			Convey("Should return not provisioned error", func() {
+				ok, err := g.CanDetachFromProvisioning()
+				So(err, ShouldEqual, m.ErrDashboardNotProvisioned)
+				So(ok, ShouldBeFalse)
+			})
+		})
+
+		Convey("Given editor of a provisioned dashboard", func() {
+			g := newTestGuardian(m.ROLE_VIEWER, map[int64][]*m.DashboardAclInfoDTO{
+				dashboardID: {toDto(newDefaultUserPermission(dashboardID, m.PERMISSION_EDIT))},
+			})
+			setupProvisioning(true)
+
+			Convey("Should not be allowed to detach from provisioning", func() {
+				ok, err := g.CanDetachFromProvisioning()
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeFalse)
+			})
+		})
+	})
+}
+
+func TestGuardianCanGrantRole(t *testing.T) {
+	Convey("Guardian grant role tests", t, func() {
+		Convey("Given user is org admin", func() {
+			g := newTestGuardian(m.ROLE_ADMIN, map[int64][]*m.DashboardAclInfoDTO{})
+
+			Convey("Should be allowed to grant the editor role", func() {
+				ok, err := g.CanGrantRole(m.ROLE_EDITOR)
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeTrue)
+			})
+		})
+
+		Convey("Given editor is admin of the dashboard", func() {
+			g := newTestGuardian(m.ROLE_EDITOR, map[int64][]*m.DashboardAclInfoDTO{
+				dashboardID: {toDto(newDefaultUserPermission(dashboardID, m.PERMISSION_ADMIN))},
+			})
+
+			Convey("Should be allowed to grant the viewer role", func() {
+				ok, err := g.CanGrantRole(m.ROLE_VIEWER)
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeTrue)
+			})
+
+			Convey("Should be denied when granting the admin role", func() {
+				ok, err := g.CanGrantRole(m.ROLE_ADMIN)
+				So(err, ShouldEqual, ErrGuardianGrantExceedsOwn)
+				So(ok, ShouldBeFalse)
+			})
+		})
+
+		Convey("Given editor can edit the dashboard", func() {
+			g := newTestGuardian(m.ROLE_EDITOR, map[int64][]*m.DashboardAclInfoDTO{
+				dashboardID: {toDto(newDefaultUserPermission(dashboardID, m.PERMISSION_EDIT))},
+			})
+
+			Convey("Should not be allowed to grant a role", func() {
+				ok, err := g.CanGrantRole(m.ROLE_VIEWER)
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeFalse)
+			})
+		})
+	})
+}
+
+func TestGuardianCanMerge(t *testing.T) {
+	Convey("Guardian merge tests", t, func() {
+		Convey("Given user can edit the target and view the source", func() {
+			g := newTestGuardian(m.ROLE_VIEWER, map[int64][]*m.DashboardAclInfoDTO{
+				dashboardID:      {toDto(newDefaultUserPermission(dashboardID, m.PERMISSION_EDIT))},
+				otherDashboardID: {toDto(newDefaultUserPermission(otherDashboardID, m.PERMISSION_VIEW))},
+			})
+
+			Convey("Should be allowed to merge", func() {
+				ok, err := g.CanMerge(otherDashboardID)
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeTrue)
+			})
+		})
+
+		Convey("Given user can edit the target but cannot view the source", func() {
+			g := newTestGuardian(m.ROLE_VIEWER, map[int64][]*m.DashboardAclInfoDTO{
+				dashboardID: {toDto(newDefaultUserPermission(dashboardID, m.PERMISSION_EDIT))},
+			})
+
+			Convey("Should be denied with the source requirement", func() {
+				ok, err := g.CanMerge(otherDashboardID)
+				So(err, ShouldResemble, PermissionDeniedError{DashboardId: otherDashboardID, Requirement: "View"})
+				So(ok, ShouldBeFalse)
+			})
+		})
+
+		Convey("Given user can only view the target", func() {
+			g := newTestGuardian(m.ROLE_VIEWER, map[int64][]*m.DashboardAclInfoDTO{
+				dashboardID:      {toDto(newDefaultUserPermission(dashboardID, m.PERMISSION_VIEW))},
+				otherDashboardID: {toDto(newDefaultUserPermission(otherDashboardID, m.PERMISSION_VIEW))},
+			})
+
+			Convey("Should be denied with the target requirement", func() {
+				ok, err := g.CanMerge(otherDashboardID)
+				So(err, ShouldResemble, PermissionDeniedError{DashboardId: dashboardID, Requirement: "Edit"})
+				So(ok, ShouldBeFalse)
+			})
+		})
+	})
+}
+
+func TestGuardianCanBulkStarFolder(t *testing.T) {
+	Convey("Guardian bulk star folder tests", t, func() {
+		Convey("Given folder with a viewable and a non viewable dashboard", func() {
+			g := newTestFolderGuardian(m.ROLE_VIEWER, map[int64][]*m.DashboardAclInfoDTO{})
+			setupTestFolderChildren([]*m.DashboardPermissionForUser{
+				{DashboardId: childDashboardID, Permission: m.PERMISSION_VIEW},
+			})
+
+			Convey("Should only allow starring the viewable dashboard", func() {
+				result, err := g.CanBulkStarFolder()
+				So(err, ShouldBeNil)
+				So(result, ShouldResemble, map[int64]bool{childDashboardID: true, otherDashboardID: false})
+			})
+		})
+
+		Convey("Given a folder only the owner has access to", func() {
+			f := setupTestRestrictedFolder(t)
+
+			Convey("Should only allow starring the dashboards shared with the user", func() {
+				result, err := New(f.folderID, orgID, f.viewer).CanBulkStarFolder()
+				So(err, ShouldBeNil)
+				So(result, ShouldResemble, map[int64]bool{f.childID: false, f.sharedID: true})
+			})
+
+			Convey("Should allow the owner to star all dashboards", func() {
+				result, err := New(f.folderID, orgID, f.owner).CanBulkStarFolder()
+				So(err, ShouldBeNil)
+				So(result, ShouldResemble, map[int64]bool{f.childID: true, f.sharedID: true})
+			})
+		})
+	})
+}
+
+func TestGuardianCanCloneToOrg(t *testing.T) {
+	Convey("Guardian clone to org tests", t, func() {
+		targetOrgID := orgID + 1
+
+		setupTargetOrgRole := func(role m.RoleType) {
+			bus.AddHandler("test", func(query *m.GetSignedInUserQuery) error {
+				query.Result = &m.SignedInUser{UserId: query.UserId, OrgId: query.OrgId, OrgRole: role}
+				return nil
+			})
+		}
+
+		acl := map[int64][]*m.DashboardAclInfoDTO{
+			0:              {toDto(newEditorRolePermission(defaultDashboardID, m.PERMISSION_EDIT)), toDto(newViewerRolePermission(defaultDashboardID, m.PERMISSION_VIEW))},
+			parentFolderID: {toDto(newEditorRolePermission(parentFolderID, m.PERMISSION_EDIT))},
+		}
+
+		serverAdmin := &m.SignedInUser{UserId: userID, OrgId: orgID, OrgRole: m.ROLE_ADMIN, IsGrafanaAdmin: true}
+
+		Convey("Given server admin is editor in the target org", func() {
+			newTestGuardian(m.ROLE_ADMIN, acl)
+			setupTargetOrgRole(m.ROLE_EDITOR)
+			g := New(dashboardID, orgID, serverAdmin)
+
+			Convey("Should be allowed to clone to the target folder", func() {
+				ok, err := g.CanCloneToOrg(targetOrgID, parentFolderID)
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeTrue)
+			})
+		})
+
+		Convey("Given server admin is viewer in the target org", func() {
+			newTestGuardian(m.ROLE_ADMIN, acl)
+			setupTargetOrgRole(m.ROLE_VIEWER)
+			g := New(dashboardID, orgID, serverAdmin)
+
+			Convey("Should be denied with the target folder requirement", func() {
+				ok, err := g.CanCloneToOrg(targetOrgID, parentFolderID)
+				So(err, ShouldResemble, PermissionDeniedError{DashboardId: parentFolderID, Requirement: "Edit"})
+				So(ok, ShouldBeFalse)
+			})
+		})
+
+		Convey("Given org admin", func() {
+			g := newTestGuardian(m.ROLE_ADMIN, acl)
+			setupTargetOrgRole(m.ROLE_ADMIN)
+
+			Convey("Should be denied with the server admin requirement", func() {
+				ok, err := g.CanCloneToOrg(targetOrgID, parentFolderID)
+				So(err, ShouldResemble, PermissionDeniedError{DashboardId: dashboardID, Requirement: "server admin"})
+				So(ok, ShouldBeFalse)
+			})
+		})
+	})
+}
+
+func TestBatchCanView(t *testing.T) {
+	Convey("Batch can view tests", t, func() {
+		bus.ClearBusHandlers()
+
+		queries := setupTestBatchAcl(userPermissionsAcl([]*m.DashboardPermissionForUser{
+			{DashboardId: dashboardID, Permission: m.PERMISSION_VIEW},
+			{DashboardId: childDashboardID, Permission: m.PERMISSION_EDIT},
+		}))
+
+		Convey("Given user can view some of the dashboards", func() {
+			user := &m.SignedInUser{UserId: userID, OrgId: orgID, OrgRole: m.ROLE_VIEWER}
+
+			Convey("Should check all dashboards in a single query", func() {
+				result, err := BatchCanView(orgID, []int64{dashboardID, childDashboardID, otherDashboardID}, user)
+				So(err, ShouldBeNil)
+				So(result, ShouldResemble, map[int64]bool{dashboardID: true, childDashboardID: true, otherDashboardID: false})
+				So(*queries, ShouldEqual, 1)
+			})
+		})
+
+		Convey("Given a folder only the owner has access to", func() {
+			f := setupTestRestrictedFolder(t)
+
+			Convey("Should only allow the dashboards shared out of the folder", func() {
+				result, err := BatchCanView(orgID, f.dashboardIDs(), f.viewer)
+				So(err, ShouldBeNil)
+				So(result, ShouldResemble, map[int64]bool{f.childID: false, f.sharedID: true, f.openID: true})
+			})
+
+			Convey("Should match CanView of a guardian per dashboard", func() {
+				for _, user := range []*m.SignedInUser{f.owner, f.viewer} {
+					result, err := BatchCanView(orgID, f.dashboardIDs(), user)
+					So(err, ShouldBeNil)
+
+					for _, id := range f.dashboardIDs() {
+						canView, err := New(id, orgID, user).CanView()
+						So(err, ShouldBeNil)
+						So(result[id], ShouldEqual, canView)
+					}
+				}
+			})
+		})
+	})
+}
+
+func TestGuardianCanPinGoldenVersion(t *testing.T) {
+	Convey("Guardian pin golden version tests", t, func() {
+		Convey("Given user has admin permission", func() {
+			g := newTestGuardian(m.ROLE_VIEWER, map[int64][]*m.DashboardAclInfoDTO{
+				dashboardID: {toDto(newDefaultUserPermission(dashboardID, m.PERMISSION_ADMIN))},
+			})
+			setupTestVersions()
+
+			Convey("Should be allowed to pin an existing version", func() {
+				ok, err := g.CanPinGoldenVersion(2)
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeTrue)
+			})
+
+			Convey("Should return not found for a missing version", func() {
+				ok, err := g.CanPinGoldenVersion(3)
+				So(err, ShouldEqual, m.ErrDashboardVersionNotFound)
+				So(ok, ShouldBeFalse)
+			})
+		})
+
+		Convey("Given user has edit permission", func() {
+			g := newTestGuardian(m.ROLE_VIEWER, map[int64][]*m.DashboardAclInfoDTO{
+				dashboardID: {toDto(newDefaultUserPermission(dashboardID, m.PERMISSION_EDIT))},
+			})
+			setupTestVersions()
+
+			Convey("Should be denied with the admin requirement", func() {
+				ok, err := g.CanPinGoldenVersion(2)
+				So(err, ShouldResemble, PermissionDeniedError{DashboardId: dashboardID, Requirement: "Admin"})
+				So(ok, ShouldBeFalse)
+			})
+		})
+	})
+}
+
+func TestGuardianCanTransferFolderAdmin(t *testing.T) {
+	Convey("Guardian transfer folder admin tests", t, func() {
+		setupOrgMembers := func() {
+			bus.AddHandler("test", func(query *m.GetSignedInUserQuery) error {
+				query.Result = &m.SignedInUser{UserId: query.UserId, OrgId: query.OrgId, OrgRole: m.ROLE_EDITOR}
+				if query.UserId != otherUserID {
+					query.Result.OrgId = -1
+					query.Result.OrgRole = ""
+				}
+				return nil
+			})
+		}
+
+		Convey("Given user is admin of the folder", func() {
+			g := newTestFolderGuardian(m.ROLE_EDITOR, map[int64][]*m.DashboardAclInfoDTO{
+				parentFolderID: {toDto(newDefaultUserPermission(parentFolderID, m.PERMISSION_ADMIN))},
+			})
+			setupOrgMembers()
+
+			Convey("Should be allowed to transfer to an org member", func() {
+				ok, err := g.CanTransferFolderAdmin(otherUserID)
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeTrue)
+			})
+
+			Convey("Should return org user not found for a user outside the org", func() {
+				ok, err := g.CanTransferFolderAdmin(otherUserID + 1)
+				So(err, ShouldEqual, m.ErrOrgUserNotFound)
+				So(ok, ShouldBeFalse)
+			})
+		})
+
+		Convey("Given user can edit the folder", func() {
+			g := newTestFolderGuardian(m.ROLE_EDITOR, map[int64][]*m.DashboardAclInfoDTO{
+				parentFolderID: {toDto(newDefaultUserPermission(parentFolderID, m.PERMISSION_EDIT))},
+			})
+			setupOrgMembers()
+
+			Convey("Should be denied with the admin requirement", func() {
+				ok, err := g.CanTransferFolderAdmin(otherUserID)
+				So(err, ShouldResemble, PermissionDeniedError{DashboardId: parentFolderID, Requirement: "Admin"})
+				So(ok, ShouldBeFalse)
+			})
+		})
+	})
+}
+
+func TestNewByUID(t *testing.T) {
+	Convey("Guardian by uid tests", t, func() {
+		setupDashboardUID := func() {
+			bus.AddHandler("test", func(query *m.GetDashboardQuery) error {
+				if query.Uid != "dash-uid" {
+					return m.ErrDashboardNotFound
+				}
+				query.Result = &m.Dashboard{Id: dashboardID, Uid: query.Uid, OrgId: query.OrgId, FolderId: parentFolderID}
+				return nil
+			})
+		}
+
+		Convey("Given user is admin of the dashboard and its folder", func() {
+			newTestGuardian(m.ROLE_VIEWER, map[int64][]*m.DashboardAclInfoDTO{
+				dashboardID:    {toDto(newDefaultUserPermission(dashboardID, m.PERMISSION_ADMIN))},
+				parentFolderID: {toDto(newDefaultUserPermission(parentFolderID, m.PERMISSION_ADMIN))},
+			})
+			dashboardQueries := 0
+			bus.AddHandler("test", func(query *m.GetDashboardQuery) error {
+				dashboardQueries++
+				query.Result = &m.Dashboard{Id: dashboardID, Uid: query.Uid, OrgId: query.OrgId, FolderId: parentFolderID}
+				return nil
+			})
+			user := &m.SignedInUser{UserId: userID, OrgId: orgID, OrgRole: m.ROLE_VIEWER}
+
+			Convey("Should reuse the resolved dashboard", func() {
+				g, err := NewByUID("dash-uid", orgID, user)
+				So(err, ShouldBeNil)
+
+				ok, err := g.CanCollapseToInherited()
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeTrue)
+				So(dashboardQueries, ShouldEqual, 1)
+			})
+		})
+
+		Convey("Given user can edit the dashboard", func() {
+			newTestGuardian(m.ROLE_VIEWER, map[int64][]*m.DashboardAclInfoDTO{
+				dashboardID: {toDto(newDefaultUserPermission(dashboardID, m.PERMISSION_EDIT))},
+			})
+			setupDashboardUID()
+			user := &m.SignedInUser{UserId: userID, OrgId: orgID, OrgRole: m.ROLE_VIEWER}
+
+			Convey("Should resolve the uid and check the dashboard permissions", func() {
+				g, err := NewByUID("dash-uid", orgID, user)
+				So(err, ShouldBeNil)
+
+				canEdit, err := g.CanEdit()
+				So(err, ShouldBeNil)
+				So(canEdit, ShouldBeTrue)
+
+				canAdmin, err := g.CanAdmin()
+				So(err, ShouldBeNil)
+				So(canAdmin, ShouldBeFalse)
+			})
+
+			Convey("Should return not found for an unknown uid", func() {
+				g, err := NewByUID("unknown", orgID, user)
+				So(err, ShouldEqual, m.ErrDashboardNotFound)
+				So(g, ShouldBeNil)
+			})
+		})
+	})
+}
+
+func TestNewByDashboard(t *testing.T) {
+	Convey("Guardian by dashboard tests", t, func() {
+		Convey("Given user is admin of the dashboard and its folder", func() {
+			newTestGuardian(m.ROLE_VIEWER, map[int64][]*m.DashboardAclInfoDTO{
+				dashboardID:    {toDto(newDefaultUserPermission(dashboardID, m.PERMISSION_ADMIN))},
+				parentFolderID: {toDto(newDefaultUserPermission(parentFolderID, m.PERMISSION_ADMIN))},
+			})
+			bus.AddHandler("test", func(query *m.GetDashboardQuery) error {
+				return m.ErrDashboardNotFound
+			})
+			user := &m.SignedInUser{UserId: userID, OrgId: orgID, OrgRole: m.ROLE_VIEWER}
+
+			Convey("Should use the given dashboard instead of looking it up", func() {
+				dash := &m.Dashboard{Id: dashboardID, OrgId: orgID, FolderId: parentFolderID}
+				g, err := NewByDashboard(dash, user)
+				So(err, ShouldBeNil)
+
+				ok, err := g.CanCollapseToInherited()
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeTrue)
+			})
+
+			Convey("Should refuse a dashboard of another organization", func() {
+				dash := &m.Dashboard{Id: dashboardID, OrgId: orgID + 1, FolderId: parentFolderID}
+				g, err := NewByDashboard(dash, user)
+				So(err, ShouldEqual, ErrGuardianOrgMismatch)
+				So(g, ShouldBeNil)
+			})
+		})
+	})
+}
+
+func TestMultiOrgGuardian(t *testing.T) {
+	Convey("Multi org guardian tests", t, func() {
+		otherOrgID, missingOrgID := orgID+1, orgID+2
+
+		newTestGuardian(m.ROLE_VIEWER, map[int64][]*m.DashboardAclInfoDTO{
+			dashboardID: {toDto(newDefaultUserPermission(dashboardID, m.PERMISSION_VIEW))},
+		})
+		bus.AddHandler("test", func(query *m.GetDashboardQuery) error {
+			switch query.OrgId {
+			case orgID:
+				query.Result = &m.Dashboard{Id: dashboardID, Uid: query.Uid, OrgId: orgID}
+			case otherOrgID:
+				query.Result = &m.Dashboard{Id: otherDashboardID, Uid: query.Uid, OrgId: otherOrgID}
+			default:
+				return m.ErrDashboardNotFound
+			}
+			return nil
+		})
+
+		Convey("Given the dashboard is shared into several orgs", func() {
+			g := NewMultiOrgGuardian("dash-uid", map[int64]*m.SignedInUser{
+				orgID:        {UserId: userID, OrgId: orgID, OrgRole: m.ROLE_VIEWER},
+				otherOrgID:   {UserId: userID, OrgId: otherOrgID, OrgRole: m.ROLE_VIEWER},
+				missingOrgID: {UserId: userID, OrgId: missingOrgID, OrgRole: m.ROLE_ADMIN},
+			})
+
+			Convey("Should check the view permission per org", func() {
+				result, err := g.CanView()
+				So(err, ShouldBeNil)
+				So(result, ShouldResemble, map[int64]bool{orgID: true, otherOrgID: false, missingOrgID: false})
+			})
+		})
+
+		Convey("Given a user signed in to another org", func() {
+			g := NewMultiOrgGuardian("dash-uid", map[int64]*m.SignedInUser{
+				otherOrgID: {UserId: userID, OrgId: orgID, OrgRole: m.ROLE_ADMIN},
+			})
+
+			Convey("Should refuse to check the org", func() {
+				result, err := g.CanView()
+				So(err, ShouldEqual, ErrGuardianOrgMismatch)
+				So(result, ShouldBeNil)
+			})
+		})
+	})
+}
+
+func TestConstantGuardians(t *testing.T) {
+	Convey("Constant guardian tests", t, func() {
+		bus.ClearBusHandlers()
+
+		Convey("Given an allow all guardian", func() {
+			g := NewAllowAllGuardian()
+
+			Convey("Should allow every check", func() {
+				canView, _ := g.CanView()
+				canAdmin, _ := g.CanAdmin()
+				canPurge, _ := g.CanPurge()
+				So(canView, ShouldBeTrue)
+				So(canAdmin, ShouldBeTrue)
+				So(canPurge, ShouldBeTrue)
+				So(g.CanSaveOrError(), ShouldBeNil)
+			})
+
+			Convey("Should keep every dashboard when filtering", func() {
+				result, err := g.FilterDashboardListResults([]int64{dashboardID, childDashboardID})
+				So(err, ShouldBeNil)
+				So(result, ShouldResemble, []int64{dashboardID, childDashboardID})
+			})
+
+			Convey("Should return empty blocked lists and folder children", func() {
+				ok, blocked, err := g.CanBulkMoveFolderContents(parentFolderID)
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeTrue)
+				So(blocked, ShouldNotBeNil)
+				So(blocked, ShouldBeEmpty)
+
+				editable, err := g.GetEditableFolderDashboards()
+				So(err, ShouldBeNil)
+				So(editable, ShouldNotBeNil)
+				So(editable, ShouldBeEmpty)
+
+				starrable, err := g.CanBulkStarFolder()
+				So(err, ShouldBeNil)
+				So(starrable, ShouldNotBeNil)
+				So(starrable, ShouldBeEmpty)
+			})
+
+			Convey("Should not report the user as sole admin", func() {
+				isSoleAdmin, err := g.IsSoleAdmin()
+				So(err, ShouldBeNil)
+				So(isSoleAdmin, ShouldBeFalse)
+			})
+
+			Convey("Should return an empty acl", func() {
+				acl, err := g.GetAcl()
+				So(err, ShouldBeNil)
+				So(acl, ShouldNotBeNil)
+				So(acl, ShouldBeEmpty)
+			})
+		})
+
+		Convey("Given a deny all guardian", func() {
+			g := NewDenyAllGuardian()
+
+			Convey("Should deny every check", func() {
+				canView, _ := g.CanView()
+				canSave, _ := g.CanSave()
+				explanation, _ := g.ExplainAccess(m.PERMISSION_VIEW)
+				So(canView, ShouldBeFalse)
+				So(canSave, ShouldBeFalse)
+				So(explanation.Allowed, ShouldBeFalse)
+				So(g.CanEditOrError(), ShouldResemble, PermissionDeniedError{Requirement: "Edit"})
+			})
+
+			Convey("Should filter out every dashboard", func() {
+				result, err := g.FilterDashboardListResults([]int64{dashboardID, childDashboardID})
+				So(err, ShouldBeNil)
+				So(result, ShouldNotBeNil)
+				So(result, ShouldBeEmpty)
+			})
+
+			Convey("Should return empty blocked lists and folder children", func() {
+				ok, blocked, err := g.CanRestoreFolder()
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeFalse)
+				So(blocked, ShouldNotBeNil)
+				So(blocked, ShouldBeEmpty)
+
+				starrable, err := g.CanBulkStarFolder()
+				So(err, ShouldBeNil)
+				So(starrable, ShouldNotBeNil)
+				So(starrable, ShouldBeEmpty)
+			})
+
+			Convey("Should return an empty acl", func() {
+				acl, err := g.GetAcl()
+				So(err, ShouldBeNil)
+				So(acl, ShouldNotBeNil)
+				So(acl, ShouldBeEmpty)
+			})
+		})
+	})
+}
+
+func TestGuardianPermissionCache(t *testing.T) {
+	Convey("Guardian permission cache tests", t, func() {
+		Convey("Given user is member of a team with edit permission", func() {
+			bus.ClearBusHandlers()
+
+			bus.AddHandler("test", func(query *m.GetDashboardAclInfoListQuery) error {
+				query.Result = []*m.DashboardAclInfoDTO{toDto(newDefaultTeamPermission(dashboardID, m.PERMISSION_EDIT))}
+				return nil
+			})
+			bus.AddHandler("test", func(query *m.GetTeamsByUserQuery) error {
+				query.Result = []*m.TeamDTO{{Id: teamID}}
+				return nil
+			})
+
+			g := New(dashboardID, orgID, &m.SignedInUser{UserId: userID, OrgId: orgID, OrgRole: m.ROLE_VIEWER})
+
+			Convey("Should only evaluate the acl on the first check of each permission", func() {
+				impl := g.(*dashboardGuardianImpl)
+				for i := 0; i < 2; i++ {
+					canEdit, err := g.CanEdit()
+					So(err, ShouldBeNil)
+					So(canEdit, ShouldBeTrue)
+
+					canAdmin, err := g.CanAdmin()
+					So(err, ShouldBeNil)
+					So(canAdmin, ShouldBeFalse)
+
+					So(impl.permissions, ShouldResemble, map[m.PermissionType]bool{
+						m.PERMISSION_EDIT:  true,
+						m.PERMISSION_ADMIN: false,
+					})
+
+					// evaluating the acl again would now deny edit
+					impl.acl = []*m.DashboardAclInfoDTO{}
+				}
+			})
+		})
+	})
+}
+
+func BenchmarkGuardianPermissionChecks(b *testing.B) {
+	bus.ClearBusHandlers()
+	bus.AddHandler("test", func(query *m.GetDashboardAclInfoListQuery) error {
+		query.Result = []*m.DashboardAclInfoDTO{
+			toDto(newEditorRolePermission(dashboardID, m.PERMISSION_EDIT)),
+			toDto(newViewerRolePermission(dashboardID, m.PERMISSION_VIEW)),
+			toDto(newDefaultUserPermission(otherDashboardID, m.PERMISSION_ADMIN)),
+			toDto(newDefaultTeamPermission(dashboardID, m.PERMISSION_ADMIN)),
+		}
+		return nil
+	})
+	bus.AddHandler("test", func(query *m.GetTeamsByUserQuery) error {
+		query.Result = []*m.TeamDTO{{Id: otherTeamID}}
+		return nil
+	})
+	user := &m.SignedInUser{UserId: userID, OrgId: orgID, OrgRole: m.ROLE_VIEWER}
+
+	var g DashboardGuardian
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		g = New(dashboardID, orgID, user)
+		g.CanView()
+		g.CanEdit()
+		g.CanSave()
+		g.CanAdmin()
+		g.CanView()
+		g.CanEdit()
+	}
+	b.StopTimer()
+
+	// the repeated checks are answered from the cache, so only view, edit and admin are evaluated
+	if permissions := g.(*dashboardGuardianImpl).permissions; len(permissions) != 3 {
+		b.Fatalf("expected 3 evaluated permissions, got %v", permissions)
+	}
+}
+
+func TestGuardianCanChangeInheritanceMode(t *testing.T) {
+	Convey("Guardian change inheritance mode tests", t, func() {
+		Convey("Given user keeps admin on every dashboard with its own permissions", func() {
+			g := newTestFolderGuardian(m.ROLE_EDITOR, map[int64][]*m.DashboardAclInfoDTO{
+				parentFolderID:   {toDto(newDefaultUserPermission(parentFolderID, m.PERMISSION_ADMIN))},
+				childDashboardID: {toDto(newDefaultUserPermission(childDashboardID, m.PERMISSION_ADMIN))},
+			})
+			setupTestFolderChildren(nil)
+
+			Convey("Should be allowed to change the inheritance mode", func() {
+				ok, err := g.CanChangeInheritanceMode()
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeTrue)
+			})
+		})
+
+		Convey("Given user is only admin of a dashboard through the folder", func() {
+			g := newTestFolderGuardian(m.ROLE_EDITOR, map[int64][]*m.DashboardAclInfoDTO{
+				parentFolderID:   {toDto(newDefaultUserPermission(parentFolderID, m.PERMISSION_ADMIN))},
+				childDashboardID: {toDto(newDefaultUserPermission(childDashboardID, m.PERMISSION_EDIT))},
+			})
+			setupTestFolderChildren(nil)
+
+			Convey("Should be denied as it would remove the last admin", func() {
+				ok, err := g.CanChangeInheritanceMode()
+				So(err, ShouldEqual, ErrWouldRemoveLastAdmin)
+				So(ok, ShouldBeFalse)
+			})
+		})
+
+		Convey("Given user can edit the folder", func() {
+			g := newTestFolderGuardian(m.ROLE_EDITOR, map[int64][]*m.DashboardAclInfoDTO{
+				parentFolderID: {toDto(newDefaultUserPermission(parentFolderID, m.PERMISSION_EDIT))},
+			})
+
+			Convey("Should not be allowed to change the inheritance mode", func() {
+				ok, err := g.CanChangeInheritanceMode()
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeFalse)
+			})
+		})
+	})
+}
+
+func TestGuardianCanSubscribeTeam(t *testing.T) {
+	Convey("Guardian subscribe team tests", t, func() {
+		setupTeamMember := func(permission m.PermissionType) {
+			bus.AddHandler("test", func(query *m.GetTeamMembersQuery) error {
+				query.Result = []*m.TeamMemberDTO{{OrgId: orgID, TeamId: teamID, UserId: userID, Permission: permission}}
+				return nil
+			})
+		}
+
+		acl := map[int64][]*m.DashboardAclInfoDTO{
+			dashboardID: {toDto(newDefaultUserPermission(dashboardID, m.PERMISSION_VIEW))},
+		}
+
+		Convey("Given user is an admin of the team", func() {
+			g := newTestGuardian(m.ROLE_EDITOR, acl)
+			setupTeamMember(m.PERMISSION_ADMIN)
+
+			Convey("Should be allowed to subscribe the team", func() {
+				ok, err := g.CanSubscribeTeam(teamID)
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeTrue)
+			})
+		})
+
+		Convey("Given user is a regular member of the team", func() {
+			g := newTestGuardian(m.ROLE_EDITOR, acl)
+			setupTeamMember(0)
+
+			Convey("Should be denied with the team admin requirement", func() {
+				ok, err := g.CanSubscribeTeam(teamID)
+				So(err, ShouldResemble, PermissionDeniedError{DashboardId: dashboardID, Requirement: "team admin"})
+				So(ok, ShouldBeFalse)
+			})
+		})
+	})
+}
+
+func TestGuardianCanImportAcl(t *testing.T) {
+	Convey("Guardian import acl tests", t, func() {
+		acl := map[int64][]*m.DashboardAclInfoDTO{
+			dashboardID: {toDto(newDefaultUserPermission(dashboardID, m.PERMISSION_ADMIN))},
+		}
+
+		Convey("Given user is admin of the dashboard", func() {
+			g := newTestGuardian(m.ROLE_EDITOR, acl)
+
+			Convey("Should be allowed to import grants within their own permissions", func() {
+				exported := []byte(`[
+					{"userId": 3, "permission": 4},
+					{"teamId": 2, "permission": 2},
+					{"role": "Editor", "permission": 1}
+				]`)
+				ok, err := g.CanImportAcl(exported)
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeTrue)
+			})
+
+			Convey("Should be denied to import a grant for a role above their own", func() {
+				exported := []byte(`[
+					{"userId": 3, "permission": 1},
+					{"role": "Admin", "permission": 2}
+				]`)
+				ok, err := g.CanImportAcl(exported)
+				So(err, ShouldResemble, GrantExceedsOwnError{Role: m.ROLE_ADMIN, Permission: m.PERMISSION_EDIT})
+				So(ok, ShouldBeFalse)
+			})
+
+			Convey("Should be denied to import a grant with an unknown permission", func() {
+				ok, err := g.CanImportAcl([]byte(`[{"userId": 3, "permission": 3}]`))
+				So(err, ShouldResemble, GrantExceedsOwnError{UserId: 3, Permission: 3})
+				So(ok, ShouldBeFalse)
+			})
+		})
+
+		Convey("Given user is org admin", func() {
+			g := newTestGuardian(m.ROLE_ADMIN, map[int64][]*m.DashboardAclInfoDTO{})
+
+			Convey("Should be allowed to import a grant for any role", func() {
+				ok, err := g.CanImportAcl([]byte(`[{"role": "Admin", "permission": 4}]`))
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeTrue)
+			})
+
+			Convey("Should be denied to import a grant with an unknown permission", func() {
+				for _, permission := range []m.PermissionType{-1, 0, 8} {
+					exported := []byte(fmt.Sprintf(`[{"teamId": 2, "permission": %d}]`, permission))
+					ok, err := g.CanImportAcl(exported)
+					So(err, ShouldResemble, GrantExceedsOwnError{TeamId: 2, Permission: permission})
+					So(ok, ShouldBeFalse)
+				}
+			})
+		})
+
+		Convey("Given user can edit the dashboard", func() {
+			g := newTestGuardian(m.ROLE_EDITOR, map[int64][]*m.DashboardAclInfoDTO{
+				dashboardID: {toDto(newDefaultUserPermission(dashboardID, m.PERMISSION_EDIT))},
+			})
+
+			Convey("Should not be allowed to import an acl", func() {
+				ok, err := g.CanImportAcl([]byte(`[]`))
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeFalse)
+			})
+		})
+	})
+}
+
+func TestGuardianCanAddToNavSection(t *testing.T) {
+	Convey("Guardian add to nav section tests", t, func() {
+		Convey("Given user is org admin", func() {
+			g := newTestGuardian(m.ROLE_ADMIN, map[int64][]*m.DashboardAclInfoDTO{})
+
+			Convey("Should be allowed to add the dashboard to a nav section", func() {
+				ok, err := g.CanAddToNavSection("monitoring")
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeTrue)
+			})
+		})
+
+		Convey("Given editor can view the dashboard", func() {
+			g := newTestGuardian(m.ROLE_EDITOR, map[int64][]*m.DashboardAclInfoDTO{
+				dashboardID: {toDto(newDefaultUserPermission(dashboardID, m.PERMISSION_ADMIN))},
+			})
+
+			Convey("Should be denied with the org admin requirement", func() {
+				ok, err := g.CanAddToNavSection("monitoring")
+				So(err, ShouldResemble, PermissionDeniedError{DashboardId: dashboardID, Requirement: "org admin"})
+				So(ok, ShouldBeFalse)
+			})
+		})
+
+		Convey("Given editor can not view the dashboard", func() {
+			g := newTestGuardian(m.ROLE_EDITOR, map[int64][]*m.DashboardAclInfoDTO{})
+
+			Convey("Should be denied with the view requirement", func() {
+				ok, err := g.CanAddToNavSection("monitoring")
+				So(err, ShouldResemble, PermissionDeniedError{DashboardId: dashboardID, Requirement: "View"})
+				So(ok, ShouldBeFalse)
+			})
+		})
+	})
+}
+
+func TestGuardianCanSaveInFolder(t *testing.T) {
+	Convey("Guardian save in folder tests", t, func() {
+		acl := map[int64][]*m.DashboardAclInfoDTO{
+			0:              {toDto(newEditorRolePermission(defaultDashboardID, m.PERMISSION_EDIT))},
+			dashboardID:    {toDto(newDefaultUserPermission(dashboardID, m.PERMISSION_VIEW))},
+			parentFolderID: {toDto(newDefaultUserPermission(parentFolderID, m.PERMISSION_EDIT))},
+			otherFolderID:  {toDto(newDefaultUserPermission(otherFolderID, m.PERMISSION_VIEW))},
+		}
+
+		Convey("Given user can edit the target folder", func() {
+			g := newTestGuardian(m.ROLE_VIEWER, acl)
+
+			Convey("Should be allowed to save in the folder", func() {
+				ok, err := g.CanSaveInFolder(parentFolderID)
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeTrue)
+			})
+
+			Convey("Should not be allowed to save in a folder they can only view", func() {
+				ok, err := g.CanSaveInFolder(otherFolderID)
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeFalse)
+			})
+
+			Convey("Should not be allowed to save in the General folder as viewer", func() {
+				ok, err := g.CanSaveInFolder(0)
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeFalse)
+			})
+		})
+
+		Convey("Given user is editor", func() {
+			g := newTestGuardian(m.ROLE_EDITOR, acl)
+
+			Convey("Should be allowed to save in the General folder", func() {
+				ok, err := g.CanSaveInFolder(0)
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeTrue)
+			})
+		})
+	})
+}
+
+func TestGuardianOrError(t *testing.T) {
+	Convey("Guardian or error tests", t, func() {
+		setupDashboards := func() {
+			bus.AddHandler("test", func(query *m.GetDashboardQuery) error {
+				query.Result = &m.Dashboard{Id: query.Id, OrgId: query.OrgId, IsFolder: query.Id == parentFolderID}
+				return nil
+			})
+		}
+
+		Convey("Given user can view the dashboard", func() {
+			g := newTestGuardian(m.ROLE_VIEWER, map[int64][]*m.DashboardAclInfoDTO{
+				dashboardID: {toDto(newDefaultUserPermission(dashboardID, m.PERMISSION_VIEW))},
+			})
+			setupDashboards()
+
+			Convey("Should return no error for view", func() {
+				So(g.CanViewOrError(), ShouldBeNil)
+			})
+
+			Convey("Should return the edit requirement for edit and save", func() {
+				expected := PermissionDeniedError{DashboardId: dashboardID, Requirement: "Edit"}
+				So(g.CanEditOrError(), ShouldResemble, expected)
+				So(g.CanSaveOrError(), ShouldResemble, expected)
+			})
+
+			Convey("Should return the admin requirement for admin", func() {
+				So(g.CanAdminOrError(), ShouldResemble, PermissionDeniedError{DashboardId: dashboardID, Requirement: "Admin"})
+			})
+		})
+
+		Convey("Given user can edit the folder", func() {
+			g := newTestFolderGuardian(m.ROLE_VIEWER, map[int64][]*m.DashboardAclInfoDTO{
+				parentFolderID: {toDto(newDefaultUserPermission(parentFolderID, m.PERMISSION_EDIT))},
+			})
+			setupDashboards()
+
+			Convey("Should return a folder denial for admin", func() {
+				err := g.CanAdminOrError()
+				So(err, ShouldResemble, PermissionDeniedError{DashboardId: parentFolderID, IsFolder: true, Requirement: "Admin"})
+				So(err.Error(), ShouldEqual, "Access denied to folder 2, requires Admin permission")
+			})
+		})
+	})
+}
+
+func TestGuardianDecisionMetrics(t *testing.T) {
+	Convey("Guardian decision metrics tests", t, func() {
+		counterValue := func(c prometheus.Counter) float64 {
+			metric := &dto.Metric{}
+			So(c.Write(metric), ShouldBeNil)
+			return metric.GetCounter().GetValue()
+		}
+
+		Convey("Given user can view the dashboard", func() {
+			g := newTestGuardian(m.ROLE_VIEWER, map[int64][]*m.DashboardAclInfoDTO{
+				dashboardID: {toDto(newDefaultUserPermission(dashboardID, m.PERMISSION_VIEW))},
+			})
+
+			Convey("Should count granted and denied checks by permission", func() {
+				granted := metrics.MGuardianDecisions.WithLabelValues("View", "granted")
+				denied := metrics.MGuardianDecisions.WithLabelValues("Admin", "denied")
+				grantedBefore, deniedBefore := counterValue(granted), counterValue(denied)
+
+				_, err := g.CanView()
+				So(err, ShouldBeNil)
+				_, err = g.CanAdmin()
+				So(err, ShouldBeNil)
+
+				So(counterValue(granted), ShouldEqual, grantedBefore+1)
+				So(counterValue(denied), ShouldEqual, deniedBefore+1)
+			})
+		})
+
+		Convey("Given the acl can not be loaded", func() {
+			g := newTestGuardian(m.ROLE_VIEWER, nil)
+			bus.AddHandler("test", func(query *m.GetDashboardAclInfoListQuery) error {
+				return errors.New("database is locked")
+			})
+
+			Convey("Should count the failed check as error", func() {
+				before := counterValue(metrics.MGuardianErrors)
+
+				_, err := g.CanView()
+				So(err, ShouldNotBeNil)
+
+				So(counterValue(metrics.MGuardianErrors), ShouldEqual, before+1)
+			})
+		})
+	})
+}
+
+func TestGuardianFilterDashboardListResults(t *testing.T) {
+	Convey("Guardian filter dashboard list results tests", t, func() {
+		Convey("Given user can only view some of the listed dashboards", func() {
+			g := newTestGuardian(m.ROLE_VIEWER, map[int64][]*m.DashboardAclInfoDTO{})
+
+			queries := setupTestBatchAcl(userPermissionsAcl([]*m.DashboardPermissionForUser{
+				{DashboardId: childDashboardID, Permission: m.PERMISSION_VIEW},
+				{DashboardId: otherDashboardID, Permission: m.PERMISSION_EDIT},
+			}))
+
+			Convey("Should only return the viewable dashboards in their original order", func() {
+				viewable, err := g.FilterDashboardListResults([]int64{otherDashboardID, dashboardID, childDashboardID})
+				So(err, ShouldBeNil)
+				So(viewable, ShouldResemble, []int64{otherDashboardID, childDashboardID})
+				So(*queries, ShouldEqual, 1)
+			})
+		})
+
+		Convey("Given the list includes a folder only the owner has access to", func() {
+			f := setupTestRestrictedFolder(t)
+			g := New(f.openID, orgID, f.viewer)
+
+			Convey("Should filter out the dashboards only inheriting the folder permissions", func() {
+				viewable, err := g.FilterDashboardListResults([]int64{f.openID, f.childID, f.sharedID})
+				So(err, ShouldBeNil)
+				So(viewable, ShouldResemble, []int64{f.openID, f.sharedID})
+			})
+		})
+	})
+}
+
+func TestGuardianEvaluateForUser(t *testing.T) {
+	Convey("Guardian evaluate for user tests", t, func() {
+		Convey("Given the dashboard grants edit to another user", func() {
+			g := newTestGuardian(m.ROLE_ADMIN, map[int64][]*m.DashboardAclInfoDTO{
+				dashboardID: {toDto(newCustomUserPermission(dashboardID, otherUserID, m.PERMISSION_EDIT))},
+			})
+			other := &m.SignedInUser{UserId: otherUserID, OrgId: orgID, OrgRole: m.ROLE_VIEWER}
+
+			Convey("Should evaluate the permissions of the other user", func() {
+				canEdit, err := g.EvaluateForUser(other, m.PERMISSION_EDIT)
+				So(err, ShouldBeNil)
+				So(canEdit, ShouldBeTrue)
+
+				canAdmin, err := g.EvaluateForUser(other, m.PERMISSION_ADMIN)
+				So(err, ShouldBeNil)
+				So(canAdmin, ShouldBeFalse)
+			})
+
+			Convey("Should refuse a user of another organization", func() {
+				ok, err := g.EvaluateForUser(&m.SignedInUser{UserId: otherUserID, OrgId: orgID + 1}, m.PERMISSION_VIEW)
+				So(err, ShouldEqual, ErrGuardianOrgMismatch)
+				So(ok, ShouldBeFalse)
+			})
+		})
+	})
+}
+
+func TestGuardianCanDeleteFolderContents(t *testing.T) {
+	Convey("Guardian delete folder contents tests", t, func() {
+		Convey("Given user can edit every dashboard in the folder", func() {
+			g := newTestFolderGuardian(m.ROLE_EDITOR, map[int64][]*m.DashboardAclInfoDTO{})
+			setupTestFolderChildren([]*m.DashboardPermissionForUser{
+				{DashboardId: childDashboardID, Permission: m.PERMISSION_EDIT},
+				{DashboardId: otherDashboardID, Permission: m.PERMISSION_ADMIN},
+			})
+
+			Convey("Should be allowed to delete the folder contents", func() {
+				ok, err := g.CanDeleteFolderContents()
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeTrue)
+			})
+		})
+
+		Convey("Given user can only view one of the dashboards", func() {
+			g := newTestFolderGuardian(m.ROLE_EDITOR, map[int64][]*m.DashboardAclInfoDTO{})
+			setupTestFolderChildren([]*m.DashboardPermissionForUser{
+				{DashboardId: childDashboardID, Permission: m.PERMISSION_EDIT},
+				{DashboardId: otherDashboardID, Permission: m.PERMISSION_VIEW},
+			})
+
+			Convey("Should not be allowed to delete the folder contents", func() {
+				ok, err := g.CanDeleteFolderContents()
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeFalse)
+			})
+		})
+
+		Convey("Given an empty folder", func() {
+			g := newTestFolderGuardian(m.ROLE_VIEWER, map[int64][]*m.DashboardAclInfoDTO{})
+			bus.AddHandler("test", func(query *m.GetDashboardsByFolderIdQuery) error {
+				query.Result = []*m.Dashboard{}
+				return nil
+			})
+
+			Convey("Should be allowed to delete the folder contents", func() {
+				ok, err := g.CanDeleteFolderContents()
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeTrue)
+			})
+		})
+	})
+}
+
+func TestGuardianCanPromoteToSharedSpace(t *testing.T) {
+	Convey("Guardian promote to shared space tests", t, func() {
+		Convey("Given user is org admin", func() {
+			g := newTestFolderGuardian(m.ROLE_ADMIN, map[int64][]*m.DashboardAclInfoDTO{})
+
+			Convey("Should be allowed to promote the folder", func() {
+				ok, err := g.CanPromoteToSharedSpace()
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeTrue)
+			})
+		})
+
+		Convey("Given editor is admin of the folder", func() {
+			g := newTestFolderGuardian(m.ROLE_EDITOR, map[int64][]*m.DashboardAclInfoDTO{
+				parentFolderID: {toDto(newDefaultUserPermission(parentFolderID, m.PERMISSION_ADMIN))},
+			})
+
+			Convey("Should be denied with the org admin requirement", func() {
+				ok, err := g.CanPromoteToSharedSpace()
+				So(err, ShouldResemble, PermissionDeniedError{DashboardId: parentFolderID, Requirement: "org admin"})
+				So(ok, ShouldBeFalse)
+			})
+		})
+
+		Convey("Given editor can edit the folder", func() {
+			g := newTestFolderGuardian(m.ROLE_EDITOR, map[int64][]*m.DashboardAclInfoDTO{
+				parentFolderID: {toDto(newDefaultUserPermission(parentFolderID, m.PERMISSION_EDIT))},
+			})
+
+			Convey("Should be denied with the admin requirement", func() {
+				ok, err := g.CanPromoteToSharedSpace()
+				So(err, ShouldResemble, PermissionDeniedError{DashboardId: parentFolderID, Requirement: "Admin"})
+				So(ok, ShouldBeFalse)
+			})
+		})
+	})
+}
+
+func TestGuardianExplainAccess(t *testing.T) {
+	Convey("Guardian explain access tests", t, func() {
+		userPermission := toDto(newDefaultUserPermission(dashboardID, m.PERMISSION_VIEW))
+		teamPermission := toDto(newDefaultTeamPermission(parentFolderID, m.PERMISSION_EDIT))
+		teamPermission.Inherited = true
+		acl := map[int64][]*m.DashboardAclInfoDTO{
+			dashboardID: {userPermission, teamPermission},
+		}
+
+		Convey("Given user is member of a team with edit on the folder", func() {
+			g := newTestGuardian(m.ROLE_VIEWER, acl)
+			bus.AddHandler("test", func(query *m.GetTeamsByUserQuery) error {
+				query.Result = []*m.TeamDTO{{Id: teamID}}
+				return nil
+			})
+
+			Convey("Should report the user permission for view", func() {
+				explanation, err := g.ExplainAccess(m.PERMISSION_VIEW)
+				So(err, ShouldBeNil)
+				So(explanation.Allowed, ShouldBeTrue)
+				So(explanation.Match, ShouldEqual, userPermission)
+				So(explanation.Acl, ShouldHaveLength, 2)
+			})
+
+			Convey("Should report the inherited team permission for edit", func() {
+				explanation, err := g.ExplainAccess(m.PERMISSION_EDIT)
+				So(err, ShouldBeNil)
+				So(explanation.Allowed, ShouldBeTrue)
+				So(explanation.Match, ShouldEqual, teamPermission)
+				So(explanation.Match.Inherited, ShouldBeTrue)
+			})
+
+			Convey("Should report no match for admin", func() {
+				explanation, err := g.ExplainAccess(m.PERMISSION_ADMIN)
+				So(err, ShouldBeNil)
+				So(explanation.Allowed, ShouldBeFalse)
+				So(explanation.Match, ShouldBeNil)
+			})
+		})
+
+		Convey("Given user is org admin", func() {
+			g := newTestGuardian(m.ROLE_ADMIN, acl)
+
+			Convey("Should be allowed as org admin without a match", func() {
+				explanation, err := g.ExplainAccess(m.PERMISSION_ADMIN)
+				So(err, ShouldBeNil)
+				So(explanation.Allowed, ShouldBeTrue)
+				So(explanation.OrgAdmin, ShouldBeTrue)
+				So(explanation.Match, ShouldBeNil)
+			})
+		})
+	})
+}
+
+func TestGuardianCanMoveOutOfLockedFolder(t *testing.T) {
+	Convey("Guardian move out of locked folder tests", t, func() {
+		setupFolders := func(locked bool) {
+			bus.AddHandler("test", func(query *m.GetDashboardQuery) error {
+				if query.Id == parentFolderID {
+					query.Result = &m.Dashboard{Id: parentFolderID, OrgId: orgID, IsFolder: true, Data: simplejson.NewFromAny(map[string]interface{}{"locked": locked})}
+					return nil
+				}
+				query.Result = &m.Dashboard{Id: query.Id, OrgId: orgID, FolderId: parentFolderID, Data: simplejson.New()}
+				return nil
+			})
+		}
+
+		aclWithFolderPermission := func(permission m.PermissionType) map[int64][]*m.DashboardAclInfoDTO {
+			return map[int64][]*m.DashboardAclInfoDTO{
+				dashboardID:    {toDto(newDefaultUserPermission(dashboardID, m.PERMISSION_EDIT))},
+				parentFolderID: {toDto(newDefaultUserPermission(parentFolderID, permission))},
+				otherFolderID:  {toDto(newDefaultUserPermission(otherFolderID, m.PERMISSION_EDIT))},
+			}
+		}
+
+		Convey("Given the folder is locked and user is admin of it", func() {
+			g := newTestGuardian(m.ROLE_EDITOR, aclWithFolderPermission(m.PERMISSION_ADMIN))
+			setupFolders(true)
+
+			Convey("Should be allowed to move the dashboard out", func() {
+				ok, err := g.CanMoveOutOfLockedFolder(otherFolderID)
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeTrue)
+			})
+		})
+
+		Convey("Given the folder is locked and user can only edit it", func() {
+			g := newTestGuardian(m.ROLE_EDITOR, aclWithFolderPermission(m.PERMISSION_EDIT))
+			setupFolders(true)
+
+			Convey("Should not be allowed to move the dashboard out", func() {
+				ok, err := g.CanMoveOutOfLockedFolder(otherFolderID)
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeFalse)
+			})
+		})
+
+		Convey("Given the folder is not locked and user can only edit it", func() {
+			g := newTestGuardian(m.ROLE_EDITOR, aclWithFolderPermission(m.PERMISSION_EDIT))
+			setupFolders(false)
+
+			Convey("Should be allowed to move the dashboard out", func() {
+				ok, err := g.CanMoveOutOfLockedFolder(otherFolderID)
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeTrue)
+			})
+		})
+	})
+}
+
+func TestGuardianCanOverrideInheritanceTemporarilyLogging(t *testing.T) {
+	Convey("Guardian override inheritance temporarily logging tests", t, func() {
+		for permission, allowed := range map[m.PermissionType]bool{m.PERMISSION_ADMIN: true, m.PERMISSION_EDIT: false} {
+			permission, allowed := permission, allowed
+
+			Convey("Given user has "+permission.String()+" permission", func() {
+				g := newTestGuardian(m.ROLE_VIEWER, map[int64][]*m.DashboardAclInfoDTO{
+					dashboardID: {toDto(newDefaultUserPermission(dashboardID, permission))},
+				})
+				logger := captureWarnings(g)
+
+				Convey("Should log the check as warning", func() {
+					g.CanOverrideInheritanceTemporarily()
+					So(logger.warnings, ShouldResemble, []testWarning{{
+						msg: "Temporary override of inherited permissions checked",
+						ctx: []interface{}{"userId", userID, "orgId", orgID, "uname", "", "dashId", dashboardID, "allowed", allowed},
+					}})
+				})
+			})
+		}
+	})
+}
+
+func TestGuardianCanStar(t *testing.T) {
+	Convey("Guardian star tests", t, func() {
+		Convey("Given user has view permission", func() {
+			g := newTestGuardian(m.ROLE_VIEWER, map[int64][]*m.DashboardAclInfoDTO{
+				dashboardID: {toDto(newDefaultUserPermission(dashboardID, m.PERMISSION_VIEW))},
+			})
+
+			Convey("Should be allowed to star", func() {
+				ok, err := g.CanStar()
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeTrue)
+			})
+		})
+
+		Convey("Given user has no permission", func() {
+			g := newTestGuardian(m.ROLE_VIEWER, map[int64][]*m.DashboardAclInfoDTO{})
+
+			Convey("Should not be allowed to star", func() {
+				ok, err := g.CanStar()
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeFalse)
+			})
+		})
+	})
+}
+
 func (sc *scenarioContext) defaultPermissionScenario(pt permissionType, flag permissionFlags) {
 	_, callerFile, callerLine, _ := runtime.Caller(1)
 	sc.callerFile = callerFile
@@ -5,9 +5,13 @@ import (
 	"fmt"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/log"
 	m "github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
 	. "github.com/smartystreets/goconvey/convey"
 )
 
@@ -275,3 +279,189 @@ func toDto(acl *m.DashboardAcl) *m.DashboardAclInfoDTO {
 		PermissionName: acl.Permission.String(),
 	}
 }
+
+func newTestGuardian(role m.RoleType, acl map[int64][]*m.DashboardAclInfoDTO) DashboardGuardian {
+	bus.ClearBusHandlers()
+
+	bus.AddHandler("test", func(query *m.GetDashboardAclInfoListQuery) error {
+		query.Result = acl[query.DashboardId]
+		return nil
+	})
+	setupTestBatchAcl(acl)
+
+	bus.AddHandler("test", func(query *m.GetTeamsByUserQuery) error {
+		query.Result = []*m.TeamDTO{}
+		return nil
+	})
+
+	user := &m.SignedInUser{UserId: userID, OrgId: orgID, OrgRole: role}
+	return New(dashboardID, orgID, user)
+}
+
+func newTestFolderGuardian(role m.RoleType, acl map[int64][]*m.DashboardAclInfoDTO) DashboardGuardian {
+	newTestGuardian(role, acl)
+
+	user := &m.SignedInUser{UserId: userID, OrgId: orgID, OrgRole: role}
+	return New(parentFolderID, orgID, user)
+}
+
+// setupTestFolderChildren puts two dashboards in the parent folder and gives the user the given
+// permissions on them
+func setupTestFolderChildren(childPermissions []*m.DashboardPermissionForUser) {
+	bus.AddHandler("test", func(query *m.GetDashboardsByFolderIdQuery) error {
+		query.Result = []*m.Dashboard{{Id: childDashboardID, FolderId: parentFolderID}, {Id: otherDashboardID, FolderId: parentFolderID}}
+		return nil
+	})
+
+	setupTestBatchAcl(userPermissionsAcl(childPermissions))
+}
+
+// setupTestBatchAcl answers the batched acl query with the given acls and returns a counter of
+// the queries made
+func setupTestBatchAcl(acl map[int64][]*m.DashboardAclInfoDTO) *int {
+	queries := 0
+	bus.AddHandler("test", func(query *m.GetDashboardsAclInfoListQuery) error {
+		queries++
+		query.Result = map[int64][]*m.DashboardAclInfoDTO{}
+		for _, id := range query.DashboardIds {
+			if items, ok := acl[id]; ok {
+				query.Result[id] = items
+			}
+		}
+		return nil
+	})
+
+	return &queries
+}
+
+// userPermissionsAcl turns permissions of the test user into an acl with a user item per dashboard
+func userPermissionsAcl(permissions []*m.DashboardPermissionForUser) map[int64][]*m.DashboardAclInfoDTO {
+	acl := map[int64][]*m.DashboardAclInfoDTO{}
+	for _, p := range permissions {
+		acl[p.DashboardId] = append(acl[p.DashboardId], toDto(newDefaultUserPermission(p.DashboardId, p.Permission)))
+	}
+
+	return acl
+}
+
+// setupTestDatasources resolves any data source name and lets the user query only the allowed ones
+func setupTestDatasources(allowed ...string) {
+	bus.AddHandler("test", func(query *m.GetDataSourceByNameQuery) error {
+		query.Result = &m.DataSource{Name: query.Name, OrgId: query.OrgId}
+		return nil
+	})
+
+	bus.AddHandler("test", func(query *m.DatasourcesPermissionFilterQuery) error {
+		query.Result = []*m.DataSource{}
+		for _, ds := range query.Datasources {
+			for _, name := range allowed {
+				if ds.Name == name {
+					query.Result = append(query.Result, ds)
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// setupTestVersions answers version lookups with version 2 of the dashboard being the only one
+func setupTestVersions() {
+	bus.AddHandler("test", func(query *m.GetDashboardVersionQuery) error {
+		if query.DashboardId != dashboardID || query.Version != 2 {
+			return m.ErrDashboardVersionNotFound
+		}
+		query.Result = &m.DashboardVersion{DashboardId: dashboardID, Version: 2}
+		return nil
+	})
+}
+
+// testRestrictedFolder is a folder only the owner has access to. Child inherits the acl of the
+// folder, shared adds admin for the viewer to it and open is in the General folder, so it keeps
+// the default permissions of the org roles
+type testRestrictedFolder struct {
+	folderID int64
+	childID  int64
+	sharedID int64
+	openID   int64
+	owner    *m.SignedInUser
+	viewer   *m.SignedInUser
+}
+
+func (f testRestrictedFolder) dashboardIDs() []int64 {
+	return []int64{f.childID, f.sharedID, f.openID}
+}
+
+// setupTestRestrictedFolder stores a testRestrictedFolder in a test database and puts the store
+// handlers used by the guardian on the bus
+func setupTestRestrictedFolder(t *testing.T) testRestrictedFolder {
+	sqlstore.InitTestDB(t)
+
+	bus.ClearBusHandlers()
+	bus.AddHandler("sql", sqlstore.GetDashboard)
+	bus.AddHandler("sql", sqlstore.GetDashboards)
+	bus.AddHandler("sql", sqlstore.GetDashboardsByFolderId)
+	bus.AddHandler("sql", sqlstore.GetDashboardAclInfoList)
+	bus.AddHandler("sql", sqlstore.GetDashboardsAclInfoList)
+	bus.AddHandler("sql", sqlstore.GetTeamsByUser)
+
+	f := testRestrictedFolder{
+		owner:  &m.SignedInUser{UserId: otherUserID, OrgId: orgID, OrgRole: m.ROLE_EDITOR},
+		viewer: &m.SignedInUser{UserId: userID, OrgId: orgID, OrgRole: m.ROLE_VIEWER},
+	}
+	f.folderID = insertTestDashboard("Restricted", 0, true)
+	f.childID = insertTestDashboard("Child", f.folderID, false)
+	f.sharedID = insertTestDashboard("Shared", f.folderID, false)
+	f.openID = insertTestDashboard("Open", 0, false)
+
+	updateTestAcl(f.folderID, &m.DashboardAcl{UserId: f.owner.UserId, Permission: m.PERMISSION_ADMIN})
+	updateTestAcl(f.sharedID, &m.DashboardAcl{UserId: f.viewer.UserId, Permission: m.PERMISSION_ADMIN})
+
+	return f
+}
+
+func insertTestDashboard(title string, folderID int64, isFolder bool) int64 {
+	cmd := m.SaveDashboardCommand{
+		OrgId:     orgID,
+		FolderId:  folderID,
+		IsFolder:  isFolder,
+		Dashboard: simplejson.NewFromAny(map[string]interface{}{"title": title}),
+	}
+	So(sqlstore.SaveDashboard(&cmd), ShouldBeNil)
+
+	return cmd.Result.Id
+}
+
+func updateTestAcl(dashboardID int64, items ...*m.DashboardAcl) {
+	for _, item := range items {
+		item.OrgId = orgID
+		item.DashboardId = dashboardID
+		item.Created = time.Now()
+		item.Updated = time.Now()
+	}
+
+	So(sqlstore.UpdateDashboardAcl(&m.UpdateDashboardAclCommand{DashboardId: dashboardID, Items: items}), ShouldBeNil)
+}
+
+type testWarning struct {
+	msg string
+	ctx []interface{}
+}
+
+// testWarnLogger records the warnings logged through it and passes everything else on
+type testWarnLogger struct {
+	log.Logger
+	warnings []testWarning
+}
+
+func (l *testWarnLogger) Warn(msg string, ctx ...interface{}) {
+	l.warnings = append(l.warnings, testWarning{msg: msg, ctx: ctx})
+}
+
+// captureWarnings replaces the logger of the guardian with one recording its warnings
+func captureWarnings(g DashboardGuardian) *testWarnLogger {
+	impl := g.(*dashboardGuardianImpl)
+	logger := &testWarnLogger{Logger: impl.log}
+	impl.log = logger
+
+	return logger
+}
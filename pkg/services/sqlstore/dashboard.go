@@ -15,6 +15,7 @@ func init() {
 	bus.AddHandler("sql", SaveDashboard)
 	bus.AddHandler("sql", GetDashboard)
 	bus.AddHandler("sql", GetDashboards)
+	bus.AddHandler("sql", GetDashboardsByFolderId)
 	bus.AddHandler("sql", DeleteDashboard)
 	bus.AddHandler("sql", SearchDashboards)
 	bus.AddHandler("sql", GetDashboardTags)
@@ -366,6 +367,15 @@ func GetDashboards(query *m.GetDashboardsQuery) error {
 	return err
 }
 
+// GetDashboardsByFolderId returns all dashboards in a folder, without any permission filtering
+func GetDashboardsByFolderId(query *m.GetDashboardsByFolderIdQuery) error {
+	var dashboards = make([]*m.Dashboard, 0)
+
+	err := x.Where("org_id=? AND folder_id=?", query.OrgId, query.FolderId).Find(&dashboards)
+	query.Result = dashboards
+	return err
+}
+
 // GetDashboardPermissionsForUser returns the maximum permission the specified user has for a dashboard(s)
 // The function takes in a list of dashboard ids and the user id and role
 func GetDashboardPermissionsForUser(query *m.GetDashboardPermissionsForUserQuery) error {
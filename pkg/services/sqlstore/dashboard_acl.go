@@ -1,6 +1,8 @@
 package sqlstore
 
 import (
+	"strings"
+
 	"github.com/grafana/grafana/pkg/bus"
 	m "github.com/grafana/grafana/pkg/models"
 )
@@ -8,6 +10,7 @@ import (
 func init() {
 	bus.AddHandler("sql", UpdateDashboardAcl)
 	bus.AddHandler("sql", GetDashboardAclInfoList)
+	bus.AddHandler("sql", GetDashboardsAclInfoList)
 }
 
 func UpdateDashboardAcl(cmd *m.UpdateDashboardAclCommand) error {
@@ -78,7 +81,69 @@ func GetDashboardAclInfoList(query *m.GetDashboardAclInfoListQuery) error {
 
 		rawSQL := `
 			-- get permissions for the dashboard and its parent folder
+			SELECT` + dashboardAclInfoSQL() + `
+			WHERE d.org_id = ? AND d.id = ? AND da.id IS NOT NULL
+			ORDER BY da.id ASC
+			`
+
+		query.Result = make([]*m.DashboardAclInfoDTO, 0)
+		err = x.SQL(rawSQL, query.OrgId, query.DashboardId).Find(&query.Result)
+	}
+
+	for _, p := range query.Result {
+		p.PermissionName = p.Permission.String()
+	}
+
+	return err
+}
+
+// GetDashboardsAclInfoList returns the permissions of several dashboards, fetched the same way as
+// GetDashboardAclInfoList but in a single query
+func GetDashboardsAclInfoList(query *m.GetDashboardsAclInfoListQuery) error {
+	query.Result = make(map[int64][]*m.DashboardAclInfoDTO, len(query.DashboardIds))
+	if len(query.DashboardIds) == 0 {
+		return nil
+	}
+
+	rawSQL := `
 			SELECT
+				d.id AS for_dashboard_id,` + dashboardAclInfoSQL() + `
+			WHERE d.org_id = ? AND d.id IN (?` + strings.Repeat(",?", len(query.DashboardIds)-1) + `) AND da.id IS NOT NULL
+			ORDER BY d.id ASC, da.id ASC
+			`
+
+	params := []interface{}{query.OrgId}
+	for _, id := range query.DashboardIds {
+		params = append(params, id)
+	}
+
+	rows := make([]*dashboardAclInfoForDashboard, 0)
+	if err := x.SQL(rawSQL, params...).Find(&rows); err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		p := row.DashboardAclInfoDTO
+		p.PermissionName = p.Permission.String()
+		query.Result[row.ForDashboardId] = append(query.Result[row.ForDashboardId], &p)
+	}
+
+	return nil
+}
+
+type dashboardAclInfoForDashboard struct {
+	ForDashboardId        int64
+	m.DashboardAclInfoDTO `xorm:"extends"`
+}
+
+// dashboardAclInfoSQL returns the columns and joins shared by the dashboard acl info queries. The
+// title, slug and uid of inherited items are the ones of the folder they are inherited from
+func dashboardAclInfoSQL() string {
+	falseStr := dialect.BooleanStr(false)
+	trueStr := dialect.BooleanStr(true)
+	inherited := `(da.dashboard_id = -1 AND d.folder_id > 0) OR da.dashboard_id = d.folder_id`
+
+	return `
 				da.id,
 				da.org_id,
 				da.dashboard_id,
@@ -92,11 +157,11 @@ func GetDashboardAclInfoList(query *m.GetDashboardAclInfoListQuery) error {
 				u.email AS user_email,
 				ug.name AS team,
 				ug.email AS team_email,
-				d.title,
-				d.slug,
-				d.uid,
-				d.is_folder,
-				CASE WHEN (da.dashboard_id = -1 AND d.folder_id > 0) OR da.dashboard_id = d.folder_id THEN ` + dialect.BooleanStr(true) + ` ELSE ` + falseStr + ` END AS inherited
+				CASE WHEN ` + inherited + ` THEN folder.title ELSE d.title END AS title,
+				CASE WHEN ` + inherited + ` THEN folder.slug ELSE d.slug END AS slug,
+				CASE WHEN ` + inherited + ` THEN folder.uid ELSE d.uid END AS uid,
+				CASE WHEN ` + inherited + ` THEN ` + trueStr + ` ELSE d.is_folder END AS is_folder,
+				CASE WHEN ` + inherited + ` THEN ` + trueStr + ` ELSE ` + falseStr + ` END AS inherited
 			FROM dashboard as d
 				LEFT JOIN dashboard folder on folder.id = d.folder_id
 				LEFT JOIN dashboard_acl AS da ON
@@ -110,18 +175,5 @@ func GetDashboardAclInfoList(query *m.GetDashboardAclInfoListQuery) error {
 					)
 				)
 				LEFT JOIN ` + dialect.Quote("user") + ` AS u ON u.id = da.user_id
-				LEFT JOIN team ug on ug.id = da.team_id
-			WHERE d.org_id = ? AND d.id = ? AND da.id IS NOT NULL
-			ORDER BY da.id ASC
-			`
-
-		query.Result = make([]*m.DashboardAclInfoDTO, 0)
-		err = x.SQL(rawSQL, query.OrgId, query.DashboardId).Find(&query.Result)
-	}
-
-	for _, p := range query.Result {
-		p.PermissionName = p.Permission.String()
-	}
-
-	return err
+				LEFT JOIN team ug on ug.id = da.team_id`
 }
@@ -116,6 +116,38 @@ func TestDashboardAclDataAccess(t *testing.T) {
 						So(query.Result[1].DashboardId, ShouldEqual, childDash.Id)
 						So(query.Result[1].Inherited, ShouldBeFalse)
 					})
+
+					Convey("When reading dashboard acl should show where the items are inherited from", func() {
+						query := m.GetDashboardAclInfoListQuery{OrgId: 1, DashboardId: childDash.Id}
+
+						err := GetDashboardAclInfoList(&query)
+						So(err, ShouldBeNil)
+
+						So(query.Result[0].Title, ShouldEqual, savedFolder.Title)
+						So(query.Result[0].Slug, ShouldEqual, savedFolder.Slug)
+						So(query.Result[0].Uid, ShouldEqual, savedFolder.Uid)
+						So(query.Result[0].IsFolder, ShouldBeTrue)
+						So(query.Result[1].Title, ShouldEqual, childDash.Title)
+						So(query.Result[1].Uid, ShouldEqual, childDash.Uid)
+						So(query.Result[1].IsFolder, ShouldBeFalse)
+					})
+
+					Convey("When reading acl of several dashboards should return the acl of each", func() {
+						query := m.GetDashboardsAclInfoListQuery{OrgId: 1, DashboardIds: []int64{savedFolder.Id, childDash.Id}}
+
+						err := GetDashboardsAclInfoList(&query)
+						So(err, ShouldBeNil)
+
+						So(len(query.Result), ShouldEqual, 2)
+						So(len(query.Result[savedFolder.Id]), ShouldEqual, 1)
+						So(query.Result[savedFolder.Id][0].DashboardId, ShouldEqual, savedFolder.Id)
+						So(query.Result[savedFolder.Id][0].Inherited, ShouldBeFalse)
+						So(query.Result[savedFolder.Id][0].PermissionName, ShouldEqual, "Edit")
+
+						single := m.GetDashboardAclInfoListQuery{OrgId: 1, DashboardId: childDash.Id}
+						So(GetDashboardAclInfoList(&single), ShouldBeNil)
+						So(query.Result[childDash.Id], ShouldResemble, single.Result)
+					})
 				})
 			})
 
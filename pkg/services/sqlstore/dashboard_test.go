@@ -56,6 +56,18 @@ func TestDashboardDataAccess(t *testing.T) {
 				So(query.Result.IsFolder, ShouldBeFalse)
 			})
 
+			Convey("Should be able to get dashboards by folder id", func() {
+				query := m.GetDashboardsByFolderIdQuery{
+					FolderId: savedFolder.Id,
+					OrgId:    1,
+				}
+
+				err := GetDashboardsByFolderId(&query)
+				So(err, ShouldBeNil)
+				So(len(query.Result), ShouldEqual, 2)
+				So(query.Result[0].FolderId, ShouldEqual, savedFolder.Id)
+			})
+
 			Convey("Should be able to get dashboard by slug", func() {
 				query := m.GetDashboardQuery{
 					Slug:  "test-dash-23",
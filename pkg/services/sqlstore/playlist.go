@@ -1,6 +1,8 @@
 package sqlstore
 
 import (
+	"strconv"
+
 	"github.com/grafana/grafana/pkg/bus"
 	m "github.com/grafana/grafana/pkg/models"
 )
@@ -12,6 +14,7 @@ func init() {
 	bus.AddHandler("sql", SearchPlaylists)
 	bus.AddHandler("sql", GetPlaylist)
 	bus.AddHandler("sql", GetPlaylistItem)
+	bus.AddHandler("sql", GetPlaylistsByDashboardId)
 }
 
 func CreatePlaylist(cmd *m.CreatePlaylistCommand) error {
@@ -155,3 +158,15 @@ func GetPlaylistItem(query *m.GetPlaylistItemsByIdQuery) error {
 
 	return err
 }
+
+func GetPlaylistsByDashboardId(query *m.GetPlaylistsByDashboardIdQuery) error {
+	var playlists = make(m.Playlists, 0)
+
+	err := x.SQL(`SELECT DISTINCT p.* FROM playlist AS p
+		INNER JOIN playlist_item AS pi ON pi.playlist_id = p.id
+		WHERE p.org_id = ? AND pi.type = 'dashboard_by_id' AND pi.value = ?
+		ORDER BY p.id`, query.OrgId, strconv.FormatInt(query.DashboardId, 10)).Find(&playlists)
+	query.Result = playlists
+
+	return err
+}
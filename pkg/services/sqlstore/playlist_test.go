@@ -22,6 +22,19 @@ func TestPlaylistDataAccess(t *testing.T) {
 			err := CreatePlaylist(&cmd)
 			So(err, ShouldBeNil)
 
+			Convey("can find the playlist by a dashboard item", func() {
+				query := m.GetPlaylistsByDashboardIdQuery{DashboardId: 3, OrgId: 1}
+				err := GetPlaylistsByDashboardId(&query)
+				So(err, ShouldBeNil)
+				So(len(query.Result), ShouldEqual, 1)
+				So(query.Result[0].Name, ShouldEqual, "NYC office")
+
+				query = m.GetPlaylistsByDashboardIdQuery{DashboardId: 3, OrgId: 2}
+				err = GetPlaylistsByDashboardId(&query)
+				So(err, ShouldBeNil)
+				So(query.Result, ShouldBeEmpty)
+			})
+
 			Convey("can update playlist", func() {
 				items := []m.PlaylistItemDTO{
 					{Title: "influxdb", Value: "influxdb", Type: "dashboard_by_tag"},
@@ -112,6 +112,9 @@ var (
 	// Dashboard history
 	DashboardVersionsToKeep int
 
+	// Dashboard edit locks
+	DashboardLockHolderPrivacy bool
+
 	// User settings
 	AllowUserSignUp         bool
 	AllowUserOrgCreate      bool
@@ -734,6 +737,7 @@ func (cfg *Cfg) Load(args *CommandLineArgs) error {
 	// read dashboard settings
 	dashboards := iniFile.Section("dashboards")
 	DashboardVersionsToKeep = dashboards.Key("versions_to_keep").MustInt(20)
+	DashboardLockHolderPrivacy = dashboards.Key("lock_holder_privacy").MustBool(false)
 
 	//  read data source proxy white list
 	DataProxyWhiteList = make(map[string]bool)